@@ -0,0 +1,148 @@
+package cronpeak
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	table "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+// CronPeakCmd represents the cron-peak command
+var CronPeakCmd = &cobra.Command{
+	Use:   "cron-peak",
+	Short: "Find the worst case footprint of the CronJobs in a rendered manifest",
+	Long: `This command walks the schedule of every CronJob (in its timeZone) over a window, eg: a day or a week,
+	assuming each run takes its expected duration: the manresca.io/expected-duration annotation of the CronJob,
+	--duration otherwise, capped at activeDeadlineSeconds. It reports the peak concurrent CPU & memory requests
+	of the CronJobs, the minute they happen at & the totals once the always-on workloads are added.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nodes, err := estimate.ParseNodeRange(nodeCount)
+		if err != nil {
+			return fmt.Errorf("error parsing the --nodes flag: %w", err)
+		}
+		windowDuration, err := parseWindow(window)
+		if err != nil {
+			return fmt.Errorf("error parsing the --window flag: %w", err)
+		}
+		if defaultDuration <= 0 {
+			return fmt.Errorf("--duration has to be positive")
+		}
+		start, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return fmt.Errorf("error parsing the --start flag (eg: 2024-06-03T00:00:00Z): %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Cron-peak called for the filepath(s) %v\n", manifestPaths)
+		result, parseErr := estimate.ProcessManifest(manifestPaths, nodes, namespace)
+		if result == nil {
+			return &estimate.ExitError{Code: estimate.ExitParseError, Err: parseErr}
+		}
+
+		simulation, err := simulate(result, start, windowDuration, defaultDuration)
+		if err != nil {
+			return &estimate.ExitError{Code: estimate.ExitParseError, Err: err}
+		}
+		renderSimulation(simulation)
+
+		if parseErr != nil {
+			return &estimate.ExitError{Code: estimate.ExitParseError, Err: parseErr}
+		}
+		return nil
+	},
+}
+
+// Start of the window when --start isn't set. Fixed (rather than eg: today) so that the report doesn't
+// change from day to day, & a Monday so that a week long window lines up with the days of the week
+const defaultStart = "2024-01-01T00:00:00Z"
+
+var (
+	manifestPaths   []string
+	namespace       string
+	nodeCount       string
+	window          string
+	startTime       string
+	defaultDuration time.Duration
+)
+
+func init() {
+	CronPeakCmd.Flags().StringArrayVarP(&manifestPaths, "filepath", "f", []string{"rendered.yml"}, "Path to a rendered manifest, a directory or a glob pattern ('-' for stdin). Can be repeated")
+	CronPeakCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace assumed for objects which don't set one (same as 'helm template -n')")
+	CronPeakCmd.Flags().StringVar(&nodeCount, "nodes", "", "No. of nodes DaemonSets run on, so that they're part of the always-on workloads: a count (eg: 5) or a range (eg: 3-10, the min is used)")
+	CronPeakCmd.Flags().StringVar(&window, "window", "1d", "How long to simulate for: a Go duration (eg: 12h) or a no. of days/weeks (eg: 1d, 1w)")
+	CronPeakCmd.Flags().StringVar(&startTime, "start", defaultStart, "Start of the window in RFC3339 (eg: 2024-06-03T00:00:00Z), a fixed Monday by default so that the report doesn't change from day to day")
+	CronPeakCmd.Flags().DurationVar(&defaultDuration, "duration", 5*time.Minute, "Expected duration of a run for CronJobs without the "+durationAnnotation+" annotation")
+}
+
+func renderSimulation(simulation *Simulation) {
+	fmt.Printf("Summary: Simulated %d CronJob(s) from %s for %s at a 1 minute resolution. Durations come from the %s annotation, --duration otherwise & are capped at activeDeadlineSeconds.\n         Requests of the always-on workloads are at their replica count (HPA/node min when it isn't set), the ones without a known count (eg: DaemonSets without --nodes) are left out\n",
+		len(simulation.CronJobs), simulation.Start.Format(time.RFC3339), simulation.Window, durationAnnotation)
+
+	t := newTable()
+	t.AppendHeader(table.Row{"Namespace", "CronJob", "Schedule", "Time Zone", "Concurrency Policy", "Duration", "Runs", "Max Concurrent Runs", "Per Run (CPU Req / Mem Req)"})
+	for _, cronJob := range simulation.CronJobs {
+		spec := cronJob.Obj.CronJobSpec
+		policy := string(spec.ConcurrencyPolicy)
+		if policy == "" {
+			policy = "Allow"
+		}
+		if spec.Suspend != nil && *spec.Suspend {
+			policy += " (suspended)"
+		}
+		cpuPerRun, memoryPerRun := perRun(cronJob.Obj, v1.ResourceCPU), perRun(cronJob.Obj, v1.ResourceMemory)
+		t.AppendRow(table.Row{cronJob.Obj.ObjNamespace, cronJob.Obj.ObjName, spec.Schedule, cronJob.Location.String(), policy, cronJob.Duration.String(),
+			strconv.Itoa(cronJob.Runs), strconv.Itoa(cronJob.MaxConcurrent), cpuPerRun.String() + " / " + memoryPerRun.String()})
+	}
+	t.Render()
+
+	t = newTable()
+	t.AppendHeader(table.Row{"", "CPU Request", "Memory Request", "At"})
+	t.AppendRow(table.Row{"Always-on workloads", printQuantity(simulation.AlwaysOn, v1.ResourceCPU), printQuantity(simulation.AlwaysOn, v1.ResourceMemory), "_"})
+	for _, peak := range []struct {
+		name string
+		peak Peak
+	}{{"CPU", simulation.CPUPeak}, {"memory", simulation.MemoryPeak}} {
+		at := "_"
+		if !peak.peak.At.IsZero() {
+			at = peak.peak.At.Format("2006-01-02 15:04 MST (Mon)")
+		}
+		total := v1.ResourceList{}
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			sum := simulation.AlwaysOn[name].DeepCopy()
+			sum.Add(peak.peak.Requests[name])
+			total[name] = sum
+		}
+		t.AppendRow(table.Row{"CronJobs at their " + peak.name + " peak", printQuantity(peak.peak.Requests, v1.ResourceCPU), printQuantity(peak.peak.Requests, v1.ResourceMemory), at})
+		t.AppendRow(table.Row{"Total at the CronJobs' " + peak.name + " peak", printQuantity(total, v1.ResourceCPU), printQuantity(total, v1.ResourceMemory), at})
+	}
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, AlignHeader: text.AlignCenter, Align: text.AlignLeft},
+		{Number: 2, AlignHeader: text.AlignCenter, Align: text.AlignCenter},
+		{Number: 3, AlignHeader: text.AlignCenter, Align: text.AlignCenter},
+		{Number: 4, AlignHeader: text.AlignCenter, Align: text.AlignCenter},
+	})
+	t.Render()
+}
+
+func newTable() table.Writer {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	t.Style().Box.PaddingRight = "  "
+	return t
+}
+
+func printQuantity(list v1.ResourceList, name v1.ResourceName) string {
+	qty, exists := list[name]
+	if !exists || qty.IsZero() {
+		return "_"
+	}
+	return qty.String()
+}
@@ -0,0 +1,239 @@
+package cronpeak
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Annotation on a CronJob holding how long a run is expected to take (a Go duration, eg: 15m or 1h30m).
+// Takes precedence over --duration
+const durationAnnotation = "manresca.io/expected-duration"
+
+// Kinds which don't run all the time, so they aren't part of the always-on footprint
+var onDemandKinds = map[string]bool{"Job": true, "CronJob": true, "ScaledJob": true}
+
+// Result of walking every CronJob's schedule over the window, minute by minute
+type Simulation struct {
+	Start      time.Time
+	Window     time.Duration
+	CronJobs   []*CronJobRuns
+	AlwaysOn   v1.ResourceList // requests of every other workload (see addAlwaysOn)
+	CPUPeak    Peak
+	MemoryPeak Peak
+}
+
+// What a single CronJob does within the window
+type CronJobRuns struct {
+	Obj           *estimate.ObjDetail
+	Location      *time.Location
+	Duration      time.Duration // expected duration of a run
+	Runs          int           // started within the window (plus the ones still running into it)
+	MaxConcurrent int
+}
+
+// Requests of all the CronJob runs active during the minute their CPU (or memory) requests peak.
+// At is the zero time when no run is active at all
+type Peak struct {
+	At       time.Time
+	Requests v1.ResourceList
+}
+
+type run struct {
+	start, end time.Time
+}
+
+// Simulates the runs of every CronJob in [start, start+window) at a 1 minute resolution.
+// A run is assumed to take its expected duration (see expectedDuration) & the concurrencyPolicy
+// is honoured: Forbid skips a run while the previous one is still active & Replace cuts it short.
+// Suspended CronJobs don't run at all.
+func simulate(renderData *estimate.AllObjDetail, start time.Time, window time.Duration, defaultDuration time.Duration) (*Simulation, error) {
+	result := &Simulation{Start: start, Window: window, AlwaysOn: v1.ResourceList{}}
+	minutes := int((window + time.Minute - 1) / time.Minute)
+
+	// per minute change in requests, added up once every run is in
+	cpuDeltas := make([]resource.Quantity, minutes+1)
+	memoryDeltas := make([]resource.Quantity, minutes+1)
+
+	for _, obj := range renderData.SortedObjects() {
+		if !onDemandKinds[obj.ObjKind] {
+			addAlwaysOn(result.AlwaysOn, obj)
+			continue
+		}
+		if obj.CronJobSpec == nil {
+			continue
+		}
+
+		cronJob, runs, err := simulateCronJob(obj, start, window, defaultDuration)
+		if err != nil {
+			return nil, fmt.Errorf("CronJob %s/%s: %w", obj.ObjNamespace, obj.ObjName, err)
+		}
+		result.CronJobs = append(result.CronJobs, cronJob)
+
+		cpuPerRun, memoryPerRun := perRun(obj, v1.ResourceCPU), perRun(obj, v1.ResourceMemory)
+		concurrent := make([]int, minutes+1)
+		for _, r := range runs {
+			first, last := minuteIndex(start, r.start, minutes, false), minuteIndex(start, r.end, minutes, true)
+			if first >= last {
+				continue
+			}
+			cpuDeltas[first].Add(cpuPerRun)
+			cpuDeltas[last].Sub(cpuPerRun)
+			memoryDeltas[first].Add(memoryPerRun)
+			memoryDeltas[last].Sub(memoryPerRun)
+			concurrent[first]++
+			concurrent[last]--
+		}
+		active := 0
+		for _, delta := range concurrent[:minutes] {
+			active += delta
+			if active > cronJob.MaxConcurrent {
+				cronJob.MaxConcurrent = active
+			}
+		}
+	}
+
+	var cpu, memory resource.Quantity
+	var cpuPeak, memoryPeak resource.Quantity
+	for minute := 0; minute < minutes; minute++ {
+		cpu.Add(cpuDeltas[minute])
+		memory.Add(memoryDeltas[minute])
+		at := start.Add(time.Duration(minute) * time.Minute)
+		// the earliest minute wins a tie
+		if cpu.Cmp(cpuPeak) > 0 {
+			cpuPeak = cpu.DeepCopy()
+			result.CPUPeak = Peak{At: at, Requests: v1.ResourceList{v1.ResourceCPU: cpu.DeepCopy(), v1.ResourceMemory: memory.DeepCopy()}}
+		}
+		if memory.Cmp(memoryPeak) > 0 {
+			memoryPeak = memory.DeepCopy()
+			result.MemoryPeak = Peak{At: at, Requests: v1.ResourceList{v1.ResourceCPU: cpu.DeepCopy(), v1.ResourceMemory: memory.DeepCopy()}}
+		}
+	}
+	return result, nil
+}
+
+// Walks a CronJob's schedule & returns its runs overlapping the window
+func simulateCronJob(obj *estimate.ObjDetail, start time.Time, window time.Duration, defaultDuration time.Duration) (*CronJobRuns, []run, error) {
+	schedule, location, err := estimate.ParseCronSchedule(*obj.CronJobSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	duration, err := expectedDuration(obj, defaultDuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &CronJobRuns{Obj: obj, Location: location, Duration: duration}
+	if obj.CronJobSpec.Suspend != nil && *obj.CronJobSpec.Suspend {
+		return result, nil, nil
+	}
+
+	// runs started before the window can still be active at its start
+	end := start.Add(window)
+	var runs []run
+	for activation := schedule.Next(start.Add(-duration).In(location)); !activation.IsZero() && activation.Before(end); activation = schedule.Next(activation) {
+		if len(runs) > 0 && activation.Before(runs[len(runs)-1].end) {
+			switch obj.CronJobSpec.ConcurrencyPolicy {
+			case batchv1.ForbidConcurrent:
+				continue
+			case batchv1.ReplaceConcurrent:
+				runs[len(runs)-1].end = activation
+			}
+		}
+		runs = append(runs, run{start: activation, end: activation.Add(duration)})
+	}
+	result.Runs = len(runs)
+	return result, runs, nil
+}
+
+// How long a run of the CronJob is expected to take: its manresca.io/expected-duration annotation,
+// defaultDuration otherwise. Either way, a run can't outlast the Job's activeDeadlineSeconds.
+func expectedDuration(obj *estimate.ObjDetail, defaultDuration time.Duration) (time.Duration, error) {
+	duration := defaultDuration
+	if value, exists := obj.Annotations[durationAnnotation]; exists {
+		var err error
+		duration, err = time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s annotation %q: %w", durationAnnotation, value, err)
+		}
+	}
+	if deadline := obj.CronJobSpec.JobTemplate.Spec.ActiveDeadlineSeconds; deadline != nil && time.Duration(*deadline)*time.Second < duration {
+		duration = time.Duration(*deadline) * time.Second
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("expected duration %s has to be positive", duration)
+	}
+	return duration, nil
+}
+
+// Requests of all the pods of a single run
+func perRun(obj *estimate.ObjDetail, name v1.ResourceName) resource.Quantity {
+	total := obj.Resources.Requests[name].DeepCopy()
+	total.Mul(int64(obj.Replicas))
+	return total
+}
+
+// Workloads are taken at their replica count, or their min (HPA min, min of a --nodes range) when
+// the replica count isn't set. Ones without either (eg: DaemonSets without --nodes) are left out
+func addAlwaysOn(alwaysOn v1.ResourceList, obj *estimate.ObjDetail) {
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		total := obj.TotalResourceForWholeObj.Requests[name][0]
+		if total == nil {
+			total = obj.TotalResourceForWholeObj.Requests[name][1]
+		}
+		if total != nil {
+			sum := alwaysOn[name]
+			sum.Add(*total)
+			alwaysOn[name] = sum
+		}
+	}
+}
+
+// Minute of the window a point in time falls into, clamped to the window.
+// roundUp is for the end of a run, which keeps the minute it ends in busy
+func minuteIndex(start time.Time, at time.Time, minutes int, roundUp bool) int {
+	offset := at.Sub(start)
+	index := int(offset / time.Minute)
+	if roundUp && offset%time.Minute > 0 {
+		index++
+	}
+	if offset < 0 {
+		index = 0
+	}
+	if index > minutes {
+		index = minutes
+	}
+	return index
+}
+
+// Parses the --window flag: a Go duration (eg: 12h) or a no. of days/weeks (eg: 1d, 1w)
+func parseWindow(window string) (time.Duration, error) {
+	var result time.Duration
+	switch {
+	case strings.HasSuffix(window, "d"), strings.HasSuffix(window, "w"):
+		count, err := strconv.Atoi(window[:len(window)-1])
+		if err != nil {
+			return 0, fmt.Errorf("%q isn't a no. of days or weeks (eg: 1d, 1w)", window)
+		}
+		unit := 24 * time.Hour
+		if strings.HasSuffix(window, "w") {
+			unit *= 7
+		}
+		result = time.Duration(count) * unit
+	default:
+		var err error
+		result, err = time.ParseDuration(window)
+		if err != nil {
+			return 0, fmt.Errorf("%q isn't a duration (eg: 12h, 1d, 1w): %w", window, err)
+		}
+	}
+	if result < time.Minute {
+		return 0, fmt.Errorf("window %q has to be at least a minute long", window)
+	}
+	return result, nil
+}
@@ -0,0 +1,224 @@
+package cronpeak
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CronJob requesting 1 cpu & 1Gi per run, spec & jobSpec being extra (indented) fields of its spec & job spec
+func cronJob(name string, schedule string, annotations string, spec string, jobSpec string) string {
+	return `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: ` + name + `
+  annotations: {` + annotations + `}
+spec:
+  schedule: "` + schedule + `"
+` + spec + `
+  jobTemplate:
+    spec:
+` + jobSpec + `
+      template:
+        spec:
+          restartPolicy: Never
+          containers:
+          - name: job
+            image: job
+            resources:
+              requests:
+                cpu: "1"
+                memory: 1Gi
+---`
+}
+
+func simulateManifest(t *testing.T, manifest string, window time.Duration) *Simulation {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := estimate.ProcessManifest([]string{path}, estimate.NodeRange{Min: -1, Max: -1}, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, err := time.Parse(time.RFC3339, defaultStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simulation, err := simulate(result, start, window, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return simulation
+}
+
+func TestSimulateCronJob(t *testing.T) {
+	tests := []struct {
+		name              string
+		manifest          string
+		window            time.Duration
+		wantDuration      time.Duration
+		wantRuns          int
+		wantMaxConcurrent int
+		wantPeakCPU       string
+		wantPeakAt        string // RFC3339, empty when nothing runs
+	}{
+		{
+			// runs from before the window (at -20m & -10m) are still active at its start
+			name:              "allow overlapping runs",
+			manifest:          cronJob("sync", "*/10 * * * *", `manresca.io/expected-duration: 25m`, "", ""),
+			window:            time.Hour,
+			wantDuration:      25 * time.Minute,
+			wantRuns:          8,
+			wantMaxConcurrent: 3,
+			wantPeakCPU:       "3",
+			wantPeakAt:        "2024-01-01T00:00:00Z",
+		},
+		{
+			// -20m runs till 5m, so the runs at -10m, 0m, 20m, 30m & 50m are skipped
+			name:              "forbid skips runs",
+			manifest:          cronJob("sync", "*/10 * * * *", `manresca.io/expected-duration: 25m`, "  concurrencyPolicy: Forbid", ""),
+			window:            time.Hour,
+			wantDuration:      25 * time.Minute,
+			wantRuns:          3,
+			wantMaxConcurrent: 1,
+			wantPeakCPU:       "1",
+			wantPeakAt:        "2024-01-01T00:00:00Z",
+		},
+		{
+			name:              "replace cuts runs short",
+			manifest:          cronJob("sync", "*/10 * * * *", `manresca.io/expected-duration: 25m`, "  concurrencyPolicy: Replace", ""),
+			window:            time.Hour,
+			wantDuration:      25 * time.Minute,
+			wantRuns:          8,
+			wantMaxConcurrent: 1,
+			wantPeakCPU:       "1",
+			wantPeakAt:        "2024-01-01T00:00:00Z",
+		},
+		{
+			// 09:00 in Kolkata (UTC+05:30) is 03:30 UTC
+			name:              "time zone",
+			manifest:          cronJob("report", "0 9 * * *", "", "  timeZone: Asia/Kolkata", ""),
+			window:            24 * time.Hour,
+			wantDuration:      5 * time.Minute,
+			wantRuns:          1,
+			wantMaxConcurrent: 1,
+			wantPeakCPU:       "1",
+			wantPeakAt:        "2024-01-01T03:30:00Z",
+		},
+		{
+			name:         "suspended",
+			manifest:     cronJob("report", "*/5 * * * *", "", "  suspend: true", ""),
+			window:       24 * time.Hour,
+			wantDuration: 5 * time.Minute,
+			wantPeakCPU:  "0",
+		},
+		{
+			// the 1h runs would overlap, but activeDeadlineSeconds stops each one after 10m
+			name:              "active deadline caps the duration",
+			manifest:          cronJob("sync", "*/30 * * * *", `manresca.io/expected-duration: 1h`, "", "      activeDeadlineSeconds: 600"),
+			window:            2 * time.Hour,
+			wantDuration:      10 * time.Minute,
+			wantRuns:          4,
+			wantMaxConcurrent: 1,
+			wantPeakCPU:       "1",
+			wantPeakAt:        "2024-01-01T00:00:00Z",
+		},
+		{
+			// the window starts on a Monday, so a weekly run on Wednesdays falls on its 3rd day
+			name:              "runs of a day within a week",
+			manifest:          cronJob("weekly", "0 6 * * 3", "", "", ""),
+			window:            7 * 24 * time.Hour,
+			wantDuration:      5 * time.Minute,
+			wantRuns:          1,
+			wantMaxConcurrent: 1,
+			wantPeakCPU:       "1",
+			wantPeakAt:        "2024-01-03T06:00:00Z",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			simulation := simulateManifest(t, test.manifest, test.window)
+			if len(simulation.CronJobs) != 1 {
+				t.Fatalf("simulate() CronJobs = %d, want 1", len(simulation.CronJobs))
+			}
+			runs := simulation.CronJobs[0]
+			if runs.Duration != test.wantDuration || runs.Runs != test.wantRuns || runs.MaxConcurrent != test.wantMaxConcurrent {
+				t.Errorf("simulate() duration %s, %d runs, %d concurrent, want %s, %d runs, %d concurrent",
+					runs.Duration, runs.Runs, runs.MaxConcurrent, test.wantDuration, test.wantRuns, test.wantMaxConcurrent)
+			}
+			peakCPU := simulation.CPUPeak.Requests[v1.ResourceCPU]
+			if peakCPU.Cmp(resource.MustParse(test.wantPeakCPU)) != 0 {
+				t.Errorf("simulate() cpu peak = %s, want %s", peakCPU.String(), test.wantPeakCPU)
+			}
+			gotAt := ""
+			if !simulation.CPUPeak.At.IsZero() {
+				gotAt = simulation.CPUPeak.At.UTC().Format(time.RFC3339)
+			}
+			if gotAt != test.wantPeakAt {
+				t.Errorf("simulate() cpu peak at %q, want %q", gotAt, test.wantPeakAt)
+			}
+		})
+	}
+}
+
+func TestSimulatePeakTieBreak(t *testing.T) {
+	// both CronJobs request the same, so their peaks tie & the earlier one is reported
+	manifest := cronJob("early", "0 1 * * *", "", "", "") + cronJob("late", "0 5 * * *", "", "", "")
+	simulation := simulateManifest(t, manifest, 24*time.Hour)
+
+	for name, peak := range map[string]Peak{"cpu": simulation.CPUPeak, "memory": simulation.MemoryPeak} {
+		if got, want := peak.At.UTC().Format(time.RFC3339), "2024-01-01T01:00:00Z"; got != want {
+			t.Errorf("%s peak at %s, want the earliest minute %s", name, got, want)
+		}
+	}
+}
+
+func TestSimulateAlwaysOn(t *testing.T) {
+	manifest := cronJob("sync", "0 * * * *", "", "", "") + `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api
+        resources:
+          requests:
+            cpu: 500m
+            memory: 256Mi
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: migrate
+        image: migrate
+        resources:
+          requests:
+            cpu: "4"
+`
+	simulation := simulateManifest(t, manifest, time.Hour)
+
+	cpu, memory := simulation.AlwaysOn[v1.ResourceCPU], simulation.AlwaysOn[v1.ResourceMemory]
+	if cpu.Cmp(resource.MustParse("1500m")) != 0 || memory.Cmp(resource.MustParse("768Mi")) != 0 {
+		t.Errorf("simulate() always-on = %s / %s, want 1500m / 768Mi (Jobs aren't always on)", cpu.String(), memory.String())
+	}
+}
@@ -0,0 +1,235 @@
+package diff
+
+import (
+	"sort"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// How an object changed between the 2 manifests
+const (
+	changeAdded     = "added"
+	changeRemoved   = "removed"
+	changeModified  = "changed"
+	changeUnchanged = "unchanged"
+)
+
+// This datastructure collects the differences
+// between the old (-a) & the new (-b) manifest
+type ManifestDiff struct {
+	Objects        []*ObjDiff
+	ResourceNames  []v1.ResourceName // union of the resources found in both manifests
+	StorageClasses []string          // union of the StorageClasses claimed in both manifests
+	GrossDelta     ResourceDeltas    // change in GrossTotalResources & GrossTotalStorage
+	Unchanged      int               // no. of objects present in both manifests with the same replicas, pod resources, totals & storage
+}
+
+// Differences of a single object, matched by namespace, kind & name
+type ObjDiff struct {
+	Change    string
+	Kind      string
+	Namespace string
+	Name      string
+	Old       *estimate.ObjDetail // nil when the object was added
+	New       *estimate.ObjDetail // nil when the object was removed
+	Deltas    ResourceDeltas
+}
+
+// New minus old totals, keyed by the resource name (StorageClass for storage).
+// Schema: [ rep, min, max, peak ]. A resource which a side doesn't have at all counts as zero there
+// (eg: the object was added), but a total which isn't known on a side (nil, eg: a DaemonSet without
+// --nodes) leaves the delta unknown (nil) as well.
+type ResourceDeltas struct {
+	Requests map[v1.ResourceName]estimate.Bounds
+	Limits   map[v1.ResourceName]estimate.Bounds
+	Storage  map[string]estimate.Bounds
+}
+
+type objKey struct {
+	namespace, kind, name string
+}
+
+// Matches the objects of both manifests by identity & works out the deltas
+func compareManifests(oldResult *estimate.AllObjDetail, newResult *estimate.AllObjDetail) *ManifestDiff {
+	result := &ManifestDiff{ResourceNames: mergeResourceNames(oldResult.ResourceNames(), newResult.ResourceNames())}
+
+	oldObjects := map[objKey]*estimate.ObjDetail{}
+	for _, obj := range oldResult.SortedObjects() {
+		oldObjects[objKey{obj.ObjNamespace, obj.ObjKind, obj.ObjName}] = obj
+	}
+	newObjects := map[objKey]*estimate.ObjDetail{}
+	for _, obj := range newResult.SortedObjects() {
+		newObjects[objKey{obj.ObjNamespace, obj.ObjKind, obj.ObjName}] = obj
+	}
+
+	// walk the old manifest first so that removed & changed objects keep its ordering,
+	// objects which only exist in the new one come after
+	for _, obj := range oldResult.SortedObjects() {
+		key := objKey{obj.ObjNamespace, obj.ObjKind, obj.ObjName}
+		result.addObject(key, obj, newObjects[key])
+	}
+	for _, obj := range newResult.SortedObjects() {
+		key := objKey{obj.ObjNamespace, obj.ObjKind, obj.ObjName}
+		if _, exists := oldObjects[key]; !exists {
+			result.addObject(key, nil, obj)
+		}
+	}
+
+	result.GrossDelta = subtractTotals(oldResult.GrossTotalResources, newResult.GrossTotalResources)
+	result.GrossDelta.Storage = subtractBounds(oldResult.GrossTotalStorage, newResult.GrossTotalStorage)
+	result.StorageClasses = storageClasses(oldResult.GrossTotalStorage, newResult.GrossTotalStorage)
+	return result
+}
+
+func (d *ManifestDiff) addObject(key objKey, oldObj *estimate.ObjDetail, newObj *estimate.ObjDetail) {
+	objDiff := &ObjDiff{Kind: key.kind, Namespace: key.namespace, Name: key.name, Old: oldObj, New: newObj}
+
+	var oldTotals, newTotals estimate.ResourceTotals
+	var oldStorage, newStorage map[string]estimate.Bounds
+	if oldObj != nil {
+		oldTotals = oldObj.TotalResourceForWholeObj
+		oldStorage = oldObj.TotalStorage
+	}
+	if newObj != nil {
+		newTotals = newObj.TotalResourceForWholeObj
+		newStorage = newObj.TotalStorage
+	}
+	objDiff.Deltas = subtractTotals(oldTotals, newTotals)
+	objDiff.Deltas.Storage = subtractBounds(oldStorage, newStorage)
+
+	switch {
+	case oldObj == nil:
+		objDiff.Change = changeAdded
+	case newObj == nil:
+		objDiff.Change = changeRemoved
+	case replicasChanged(oldObj, newObj) || podChanged(oldObj, newObj) || !isZero(objDiff.Deltas):
+		objDiff.Change = changeModified
+	default:
+		objDiff.Change = changeUnchanged
+		d.Unchanged++
+		return
+	}
+	d.Objects = append(d.Objects, objDiff)
+}
+
+func replicasChanged(oldObj *estimate.ObjDetail, newObj *estimate.ObjDetail) bool {
+	return oldObj.ReplicaCounts() != newObj.ReplicaCounts()
+}
+
+// Per pod resources & storage, which can change even when the totals aren't known
+func podChanged(oldObj *estimate.ObjDetail, newObj *estimate.ObjDetail) bool {
+	return !equalLists(oldObj.Resources.Requests, newObj.Resources.Requests) ||
+		!equalLists(oldObj.Resources.Limits, newObj.Resources.Limits) ||
+		!equalLists(oldObj.Storage, newObj.Storage)
+}
+
+func equalLists[K comparable](oldList map[K]resource.Quantity, newList map[K]resource.Quantity) bool {
+	if len(oldList) != len(newList) {
+		return false
+	}
+	for name, qty := range oldList {
+		newQty, exists := newList[name]
+		if !exists || qty.Cmp(newQty) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func subtractTotals(oldTotals estimate.ResourceTotals, newTotals estimate.ResourceTotals) ResourceDeltas {
+	return ResourceDeltas{
+		Requests: subtractBounds(oldTotals.Requests, newTotals.Requests),
+		Limits:   subtractBounds(oldTotals.Limits, newTotals.Limits),
+	}
+}
+
+// new - old for every resource (or StorageClass) found on either side.
+// Unknown on either side stays unknown, missing on a side counts as zero
+func subtractBounds[K comparable](oldBounds map[K]estimate.Bounds, newBounds map[K]estimate.Bounds) map[K]estimate.Bounds {
+	deltas := map[K]estimate.Bounds{}
+	names := map[K]bool{}
+	for name := range oldBounds {
+		names[name] = true
+	}
+	for name := range newBounds {
+		names[name] = true
+	}
+
+	for name := range names {
+		oldTotals, inOld := oldBounds[name]
+		newTotals, inNew := newBounds[name]
+		var delta estimate.Bounds
+		for i := range delta {
+			if (inOld && oldTotals[i] == nil) || (inNew && newTotals[i] == nil) {
+				continue
+			}
+			total := resource.Quantity{}
+			if inNew {
+				total = newTotals[i].DeepCopy()
+			}
+			if inOld {
+				total.Sub(*oldTotals[i])
+			}
+			delta[i] = &total
+		}
+		deltas[name] = delta
+	}
+	return deltas
+}
+
+func isZero(deltas ResourceDeltas) bool {
+	for _, list := range []map[v1.ResourceName]estimate.Bounds{deltas.Requests, deltas.Limits} {
+		for _, delta := range list {
+			if !isZeroBounds(delta) {
+				return false
+			}
+		}
+	}
+	for _, delta := range deltas.Storage {
+		if !isZeroBounds(delta) {
+			return false
+		}
+	}
+	return true
+}
+
+func isZeroBounds(delta estimate.Bounds) bool {
+	for _, qty := range delta {
+		if qty != nil && !qty.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// Every StorageClass claimed in either manifest, in alphabetical order
+func storageClasses(oldStorage map[string]estimate.Bounds, newStorage map[string]estimate.Bounds) []string {
+	seen := map[string]bool{}
+	var classes []string
+	for _, storage := range []map[string]estimate.Bounds{oldStorage, newStorage} {
+		for storageClass := range storage {
+			if !seen[storageClass] {
+				seen[storageClass] = true
+				classes = append(classes, storageClass)
+			}
+		}
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+// Keeps the order of the first list (cpu, memory & so on) & appends
+// whatever only shows up in the second one
+func mergeResourceNames(first []v1.ResourceName, second []v1.ResourceName) []v1.ResourceName {
+	seen := map[v1.ResourceName]bool{}
+	var names []v1.ResourceName
+	for _, name := range append(append([]v1.ResourceName{}, first...), second...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,240 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func deployment(name string, replicas string, cpu string) string {
+	return `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ` + name + `
+spec:
+  replicas: ` + replicas + `
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+        resources:
+          requests:
+            cpu: ` + cpu + `
+---`
+}
+
+func daemonSet(name string, cpu string) string {
+	return `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: ` + name + `
+spec:
+  template:
+    spec:
+      containers:
+      - name: agent
+        image: agent
+        resources:
+          requests:
+            cpu: ` + cpu + `
+---`
+}
+
+func statefulSet(name string, replicas string, storageClass string, size string) string {
+	return `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: ` + name + `
+spec:
+  replicas: ` + replicas + `
+  template:
+    spec:
+      containers:
+      - name: db
+        image: db
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      storageClassName: ` + storageClass + `
+      resources:
+        requests:
+          storage: ` + size + `
+---`
+}
+
+func estimateManifest(t *testing.T, manifest string) *estimate.AllObjDetail {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := estimate.ProcessManifest([]string{path}, estimate.NodeRange{Min: -1, Max: -1}, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// Bound as a string, nil ones being "unknown"
+func boundString(qty *resource.Quantity) string {
+	if qty == nil {
+		return "unknown"
+	}
+	return qty.String()
+}
+
+func TestCompareManifestsMatching(t *testing.T) {
+	oldResult := estimateManifest(t, deployment("api", "2", "100m")+deployment("worker", "1", "500m")+deployment("cache", "1", "250m")+deployment("old-job", "1", "1"))
+	newResult := estimateManifest(t, deployment("api", "3", "100m")+deployment("worker", "1", "750m")+deployment("cache", "1", "250m")+deployment("new-job", "2", "1"))
+
+	diffData := compareManifests(oldResult, newResult)
+
+	changes := map[string]string{}
+	for _, objDiff := range diffData.Objects {
+		changes[objDiff.Name] = objDiff.Change
+	}
+	want := map[string]string{"api": changeModified, "worker": changeModified, "old-job": changeRemoved, "new-job": changeAdded}
+	if len(changes) != len(want) {
+		t.Errorf("objects = %v, want %v", changes, want)
+	}
+	for name, change := range want {
+		if changes[name] != change {
+			t.Errorf("%s: change = %q, want %q", name, changes[name], change)
+		}
+	}
+	if diffData.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", diffData.Unchanged)
+	}
+
+	deltas := map[string]string{}
+	for _, objDiff := range diffData.Objects {
+		deltas[objDiff.Name] = boundString(objDiff.Deltas.Requests[v1.ResourceCPU][0])
+	}
+	for name, delta := range map[string]string{"api": "100m", "worker": "250m", "old-job": "-1", "new-job": "2"} {
+		if deltas[name] != delta {
+			t.Errorf("%s: cpu request delta = %s, want %s", name, deltas[name], delta)
+		}
+	}
+	// +100m +250m -1 +2
+	if got := boundString(diffData.GrossDelta.Requests[v1.ResourceCPU][0]); got != "1350m" {
+		t.Errorf("gross cpu request delta = %s, want 1350m", got)
+	}
+}
+
+func TestCompareManifestsStorage(t *testing.T) {
+	oldResult := estimateManifest(t, statefulSet("db", "2", "fast", "10Gi")+statefulSet("logs", "1", "slow", "50Gi"))
+	newResult := estimateManifest(t, statefulSet("db", "3", "fast", "10Gi")+statefulSet("logs", "1", "fast", "50Gi"))
+
+	diffData := compareManifests(oldResult, newResult)
+
+	if len(diffData.StorageClasses) != 2 || diffData.StorageClasses[0] != "fast" || diffData.StorageClasses[1] != "slow" {
+		t.Errorf("StorageClasses = %v, want [fast slow]", diffData.StorageClasses)
+	}
+	tests := []struct {
+		name         string
+		storageClass string
+		wantStorage  string
+	}{
+		{name: "db", storageClass: "fast", wantStorage: "10Gi"},
+		{name: "logs", storageClass: "fast", wantStorage: "50Gi"},
+		{name: "logs", storageClass: "slow", wantStorage: "-50Gi"},
+	}
+	for _, test := range tests {
+		var objDiff *ObjDiff
+		for _, candidate := range diffData.Objects {
+			if candidate.Name == test.name {
+				objDiff = candidate
+			}
+		}
+		if objDiff == nil {
+			t.Errorf("%s isn't in the diff", test.name)
+			continue
+		}
+		if got := boundString(objDiff.Deltas.Storage[test.storageClass][0]); got != test.wantStorage {
+			t.Errorf("%s: %s storage delta = %s, want %s", test.name, test.storageClass, got, test.wantStorage)
+		}
+	}
+	for storageClass, want := range map[string]string{"fast": "60Gi", "slow": "-50Gi"} {
+		if got := boundString(diffData.GrossDelta.Storage[storageClass][0]); got != want {
+			t.Errorf("gross %s storage delta = %s, want %s", storageClass, got, want)
+		}
+	}
+}
+
+func TestCompareManifestsUnknownTotals(t *testing.T) {
+	// DaemonSets without --nodes have no known totals on either side
+	oldResult := estimateManifest(t, daemonSet("agent", "100m")+daemonSet("exporter", "50m")+daemonSet("legacy", "10m"))
+	newResult := estimateManifest(t, daemonSet("agent", "200m")+daemonSet("exporter", "50m"))
+
+	diffData := compareManifests(oldResult, newResult)
+
+	changes := map[string]*ObjDiff{}
+	for _, objDiff := range diffData.Objects {
+		changes[objDiff.Name] = objDiff
+	}
+	if changes["agent"] == nil || changes["agent"].Change != changeModified {
+		t.Fatalf("agent should be changed (its per pod cpu went up), got %v", changes["agent"])
+	}
+	if changes["legacy"] == nil || changes["legacy"].Change != changeRemoved {
+		t.Fatalf("legacy should be removed, got %v", changes["legacy"])
+	}
+	if diffData.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", diffData.Unchanged)
+	}
+	for _, name := range []string{"agent", "legacy"} {
+		for i, qty := range changes[name].Deltas.Requests[v1.ResourceCPU] {
+			if qty != nil {
+				t.Errorf("%s: cpu request delta %d = %s, want it unknown", name, i, qty.String())
+			}
+		}
+	}
+	for i, qty := range diffData.GrossDelta.Requests[v1.ResourceCPU] {
+		if qty != nil {
+			t.Errorf("gross cpu request delta %d = %s, want it unknown", i, qty.String())
+		}
+	}
+}
+
+func TestSubtractBounds(t *testing.T) {
+	qty := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+	oldBounds := map[string]estimate.Bounds{
+		"changed":      {qty("1"), qty("1"), qty("2"), qty("3")},
+		"unknown peak": {qty("1"), qty("1"), qty("1"), nil},
+		"removed":      {qty("1"), qty("1"), qty("1"), qty("1")},
+	}
+	newBounds := map[string]estimate.Bounds{
+		"changed":      {qty("2"), qty("1"), qty("4"), qty("3")},
+		"unknown peak": {qty("1"), qty("1"), qty("1"), qty("2")},
+		"added":        {qty("500m"), qty("500m"), qty("500m"), nil},
+	}
+	want := map[string][4]string{
+		"changed":      {"1", "0", "2", "0"},
+		"unknown peak": {"0", "0", "0", "unknown"},
+		"removed":      {"-1", "-1", "-1", "-1"},
+		"added":        {"500m", "500m", "500m", "unknown"},
+	}
+
+	deltas := subtractBounds(oldBounds, newBounds)
+	if len(deltas) != len(want) {
+		t.Errorf("deltas = %v, want %d of them", deltas, len(want))
+	}
+	for name, wantDelta := range want {
+		for i := range wantDelta {
+			if got := boundString(deltas[name][i]); got != wantDelta[i] {
+				t.Errorf("%s: delta %d = %s, want %s", name, i, got, wantDelta[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	"github.com/spf13/cobra"
+)
+
+// DiffCmd represents the diff command
+var DiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the resources needed by 2 rendered manifests",
+	Long: `This command estimates the resources of 2 rendered manifests (eg: before & after a values change)
+	and prints the objects which were added, removed or changed along with the change in their totals.
+	Objects are matched by namespace, kind & name.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nodes, err := estimate.ParseNodeRange(nodeCount)
+		if err != nil {
+			return fmt.Errorf("error parsing the --nodes flag: %w", err)
+		}
+		if outputFormat != "table" && outputFormat != "json" {
+			return fmt.Errorf("unknown --output format %q, expected one of: table, json", outputFormat)
+		}
+
+		fmt.Fprintf(os.Stderr, "Diff called for the filepaths %s (old) & %s (new)\n", oldManifestPath, newManifestPath)
+		oldResult, oldErr := estimate.ProcessManifest([]string{oldManifestPath}, nodes, namespace)
+		newResult, newErr := estimate.ProcessManifest([]string{newManifestPath}, nodes, namespace)
+		if oldResult == nil || newResult == nil {
+			return &estimate.ExitError{Code: estimate.ExitParseError, Err: errors.Join(oldErr, newErr)}
+		}
+		diffData := compareManifests(oldResult, newResult)
+
+		switch outputFormat {
+		case "json":
+			if err := renderJSON(os.Stdout, diffData); err != nil {
+				return fmt.Errorf("error rendering the JSON diff: %w", err)
+			}
+		default:
+			renderTable(diffData)
+		}
+
+		if oldErr != nil || newErr != nil {
+			return &estimate.ExitError{Code: estimate.ExitParseError, Err: errors.Join(oldErr, newErr)}
+		}
+		return nil
+	},
+}
+
+var (
+	oldManifestPath string
+	newManifestPath string
+	nodeCount       string
+	namespace       string
+	outputFormat    string
+)
+
+func init() {
+	DiffCmd.Flags().StringVarP(&oldManifestPath, "old", "a", "", "Path to the rendered manifest before the change")
+	DiffCmd.Flags().StringVarP(&newManifestPath, "new", "b", "", "Path to the rendered manifest after the change")
+	DiffCmd.MarkFlagRequired("old")
+	DiffCmd.MarkFlagRequired("new")
+
+	DiffCmd.Flags().StringVar(&nodeCount, "nodes", "", "No. of nodes DaemonSet pods will run on (same as for estimate). Used for both manifests")
+	DiffCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace assumed for objects which don't set one (same as 'helm template -n')")
+	DiffCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Format of the diff: table or json")
+}
@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	table "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func renderTable(diffData *ManifestDiff) {
+	fmt.Printf("Summary: %d object(s) added, removed or changed, %d unchanged. Replicas are shown as old -> new (Replicas / HPA Min / HPA Max / Peak).\n         Each resource column shows new minus old totals (Replicas / Min / Max / Peak). No change is printed as an underscore and a change which can't be worked out (the total isn't known on a side) as a question mark\n", len(diffData.Objects), diffData.Unchanged)
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	t.Style().Format.Footer = text.FormatDefault
+	t.Style().Box.PaddingRight = "  "
+
+	headerTop := table.Row{"Change", "Namespace", "Kind", "Name", "Replicas"}
+	headerBottom := table.Row{"", "", "", "", "(old -> new)"}
+	for _, name := range diffData.ResourceNames {
+		headerTop = append(headerTop, string(name), string(name))
+		headerBottom = append(headerBottom, "Request Δ (Replicas / Min / Max / Peak)", "Limit Δ (Replicas / Min / Max / Peak)")
+	}
+	for _, storageClass := range diffData.StorageClasses {
+		headerTop = append(headerTop, "storage ("+storageClass+")")
+		headerBottom = append(headerBottom, "Δ (Replicas / Min / Max / Peak)")
+	}
+	t.AppendHeader(headerTop, table.RowConfig{AutoMerge: true})
+	t.AppendHeader(headerBottom)
+
+	for _, objDiff := range diffData.Objects {
+		row := table.Row{objDiff.Change, objDiff.Namespace, objDiff.Kind, objDiff.Name, printReplicaChange(objDiff)}
+		for _, name := range diffData.ResourceNames {
+			row = append(row, printDeltas(objDiff.Deltas.Requests[name]), printDeltas(objDiff.Deltas.Limits[name]))
+		}
+		for _, storageClass := range diffData.StorageClasses {
+			row = append(row, printDeltas(objDiff.Deltas.Storage[storageClass]))
+		}
+		t.AppendRow(row)
+	}
+
+	footer := table.Row{"", "", "", "", "Total Δ"}
+	for _, name := range diffData.ResourceNames {
+		footer = append(footer, printDeltas(diffData.GrossDelta.Requests[name]), printDeltas(diffData.GrossDelta.Limits[name]))
+	}
+	for _, storageClass := range diffData.StorageClasses {
+		footer = append(footer, printDeltas(diffData.GrossDelta.Storage[storageClass]))
+	}
+	t.AppendFooter(footer)
+
+	columnConfigs := []table.ColumnConfig{}
+	for colNum := 1; colNum <= 4; colNum++ {
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Number: colNum, AlignHeader: text.AlignCenter, Align: text.AlignLeft})
+	}
+	for colNum := 5; colNum <= len(headerTop); colNum++ {
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Number: colNum, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignCenter})
+	}
+	t.SetColumnConfigs(columnConfigs)
+	t.Render()
+}
+
+// Schema: old -> new, where each side is ( Replicas / HPA Min / HPA Max / Peak )
+func printReplicaChange(objDiff *ObjDiff) string {
+	return printReplicas(objDiff.Old) + "  ->  " + printReplicas(objDiff.New)
+}
+
+func printReplicas(obj *estimate.ObjDetail) string {
+	if obj == nil {
+		return "_"
+	}
+	var counts []string
+	for _, count := range obj.ReplicaCounts() {
+		if count < 0 {
+			counts = append(counts, "_")
+		} else {
+			counts = append(counts, strconv.Itoa(int(count)))
+		}
+	}
+	return strings.Join(counts, " / ")
+}
+
+// Signed deltas (eg: +500m / -1Gi), no change is printed as an underscore & unknown ones as a question mark
+func printDeltas(delta estimate.Bounds) string {
+	var parts []string
+	for _, qty := range delta {
+		parts = append(parts, signedQuantity(qty))
+	}
+	return strings.Join(parts, "\t/\t")
+}
+
+func signedQuantity(qty *resource.Quantity) string {
+	if qty == nil {
+		return "?"
+	}
+	if qty.IsZero() {
+		return "_"
+	}
+	if qty.Sign() > 0 {
+		return "+" + qty.String()
+	}
+	return qty.String()
+}
+
+// Bumped whenever a field of the JSON diff is renamed, removed or changes its meaning
+const jsonSchemaVersion = "v1"
+
+type jsonDiff struct {
+	SchemaVersion string        `json:"schemaVersion"`
+	Unchanged     int           `json:"unchanged"`
+	Objects       []jsonObjDiff `json:"objects"`
+	GrossDelta    jsonDeltas    `json:"grossDelta"`
+}
+
+type jsonObjDiff struct {
+	Change    string               `json:"change"` // added, removed or changed
+	Kind      string               `json:"kind"`
+	Namespace string               `json:"namespace"`
+	Name      string               `json:"name"`
+	Old       *estimate.JSONCounts `json:"old"` // null when the object was added
+	New       *estimate.JSONCounts `json:"new"` // null when the object was removed
+	Deltas    jsonDeltas           `json:"deltas"`
+}
+
+// new minus old as Kubernetes quantity strings (eg: "500m", "-1Gi"), null when a side's total isn't known
+type jsonDeltas struct {
+	Requests map[string]estimate.JSONBounds `json:"requests"`
+	Limits   map[string]estimate.JSONBounds `json:"limits"`
+	Storage  map[string]estimate.JSONBounds `json:"storage"` // keyed by the StorageClass
+}
+
+func renderJSON(w io.Writer, diffData *ManifestDiff) error {
+	report := jsonDiff{
+		SchemaVersion: jsonSchemaVersion,
+		Unchanged:     diffData.Unchanged,
+		Objects:       []jsonObjDiff{},
+		GrossDelta:    toJSONDeltas(diffData.GrossDelta),
+	}
+	for _, objDiff := range diffData.Objects {
+		report.Objects = append(report.Objects, jsonObjDiff{
+			Change:    objDiff.Change,
+			Kind:      objDiff.Kind,
+			Namespace: objDiff.Namespace,
+			Name:      objDiff.Name,
+			Old:       toJSONCounts(objDiff.Old),
+			New:       toJSONCounts(objDiff.New),
+			Deltas:    toJSONDeltas(objDiff.Deltas),
+		})
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal the diff: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+func toJSONCounts(obj *estimate.ObjDetail) *estimate.JSONCounts {
+	if obj == nil {
+		return nil
+	}
+	counts := estimate.ToJSONCounts(obj.ReplicaCounts())
+	return &counts
+}
+
+func toJSONDeltas(deltas ResourceDeltas) jsonDeltas {
+	result := jsonDeltas{Requests: map[string]estimate.JSONBounds{}, Limits: map[string]estimate.JSONBounds{}, Storage: map[string]estimate.JSONBounds{}}
+	for name, delta := range deltas.Requests {
+		result.Requests[string(name)] = estimate.ToJSONBounds(delta)
+	}
+	for name, delta := range deltas.Limits {
+		result.Limits[string(name)] = estimate.ToJSONBounds(delta)
+	}
+	for storageClass, delta := range deltas.Storage {
+		result.Storage[storageClass] = estimate.ToJSONBounds(delta)
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package estimate
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	yaml "sigs.k8s.io/yaml"
+)
+
+// Names of the [ rep, min, max, peak ] bounds, as used in budgets & the csv columns
+var boundNames = []string{"replicas", "min", "max", "peak"}
+
+// An upper limit on one of the gross totals.
+// Written as <requests|limits>.<resource>[.<replicas|min|max|peak>]=<quantity>, eg: requests.cpu.max=20
+// or limits.nvidia.com/gpu=4. Without a bound, every bound which is known gets checked.
+type Budget struct {
+	QtyType  string // requests or limits
+	Resource v1.ResourceName
+	Bound    int // index into [ rep, min, max, peak ], -1 for all of them
+	Max      resource.Quantity
+}
+
+// Budgets read from a --policy file. Keys have the same syntax as --budget, eg:
+//
+//	budgets:
+//	  requests.cpu.max: 20
+//	  limits.memory: 64Gi
+type budgetPolicy struct {
+	Budgets map[string]resource.Quantity `json:"budgets"`
+}
+
+// Parses a single --budget flag value
+func parseBudget(budget string) (Budget, error) {
+	key, value, found := strings.Cut(budget, "=")
+	if !found {
+		return Budget{}, fmt.Errorf("budget %q isn't of the form <requests|limits>.<resource>[.<replicas|min|max|peak>]=<quantity>", budget)
+	}
+	qty, err := resource.ParseQuantity(strings.TrimSpace(value))
+	if err != nil {
+		return Budget{}, fmt.Errorf("invalid quantity in the budget %q: %w", budget, err)
+	}
+	return newBudget(strings.TrimSpace(key), qty)
+}
+
+func newBudget(key string, qty resource.Quantity) (Budget, error) {
+	qtyType, name, found := strings.Cut(key, ".")
+	if !found || (qtyType != "requests" && qtyType != "limits") || name == "" {
+		return Budget{}, fmt.Errorf("budget key %q isn't of the form <requests|limits>.<resource>[.<replicas|min|max|peak>]", key)
+	}
+
+	// resource names can have dots of their own (eg: nvidia.com/gpu),
+	// so the bound is only split off when the last part is one of the bound names
+	result := Budget{QtyType: qtyType, Resource: v1.ResourceName(name), Bound: -1, Max: qty}
+	if i := strings.LastIndex(name, "."); i != -1 {
+		for j, boundName := range boundNames {
+			if name[i+1:] == boundName {
+				result.Resource = v1.ResourceName(name[:i])
+				result.Bound = j
+			}
+		}
+	}
+	if !isContainerResource(result.Resource) {
+		return Budget{}, fmt.Errorf("budget key %q is for an unknown resource %q, expected cpu, memory, ephemeral-storage, hugepages-<size> or an extended resource (eg: nvidia.com/gpu)", key, result.Resource)
+	}
+	return result, nil
+}
+
+// Resources a container can request or limit: the standard ones & extended resources, which are always
+// prefixed by a domain (eg: nvidia.com/gpu). Catches typos in budgets, which would otherwise never be over
+func isContainerResource(name v1.ResourceName) bool {
+	switch {
+	case name == v1.ResourceCPU, name == v1.ResourceMemory, name == v1.ResourceEphemeralStorage:
+		return true
+	case strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix) && len(name) > len(v1.ResourceHugePagesPrefix):
+		return true
+	default:
+		return strings.Contains(string(name), "/")
+	}
+}
+
+// Reads the budgets of a --policy file
+func loadBudgetPolicy(policyPath string) ([]Budget, error) {
+	rawdata, err := os.ReadFile(policyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the policy file: %w", err)
+	}
+	var policy budgetPolicy
+	if err := yaml.UnmarshalStrict(rawdata, &policy); err != nil {
+		return nil, fmt.Errorf("error parsing the policy file %s: %w", policyPath, err)
+	}
+
+	var budgets []Budget
+	for _, key := range sortedKeys(policy.Budgets) {
+		budget, err := newBudget(key, policy.Budgets[key])
+		if err != nil {
+			return nil, fmt.Errorf("policy file %s: %w", policyPath, err)
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets, nil
+}
+
+// Compares the gross totals against the budgets.
+// Returns a line per total which is over its budget, totals which aren't known are skipped.
+func checkBudgets(renderData *AllObjDetail, budgets []Budget) []string {
+	var violations []string
+	for _, budget := range budgets {
+		totals := renderData.GrossTotalResources.Requests
+		if budget.QtyType == "limits" {
+			totals = renderData.GrossTotalResources.Limits
+		}
+
+		for i, total := range totals[budget.Resource] {
+			if budget.Bound != -1 && budget.Bound != i {
+				continue
+			}
+			if total != nil && total.Cmp(budget.Max) > 0 {
+				violations = append(violations, fmt.Sprintf("%s.%s.%s is %s, over the budget of %s", budget.QtyType, budget.Resource, boundNames[i], total.String(), budget.Max.String()))
+			}
+		}
+	}
+	return violations
+}
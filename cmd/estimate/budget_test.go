@@ -0,0 +1,155 @@
+package estimate
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestParseBudget(t *testing.T) {
+	tests := []struct {
+		budget       string
+		wantQtyType  string
+		wantResource v1.ResourceName
+		wantBound    int
+		wantMax      string
+		wantErr      bool
+	}{
+		{budget: "requests.cpu.max=20", wantQtyType: "requests", wantResource: v1.ResourceCPU, wantBound: 2, wantMax: "20"},
+		{budget: "limits.memory=64Gi", wantQtyType: "limits", wantResource: v1.ResourceMemory, wantBound: -1, wantMax: "64Gi"},
+		{budget: " requests.ephemeral-storage.peak = 10Gi ", wantQtyType: "requests", wantResource: v1.ResourceEphemeralStorage, wantBound: 3, wantMax: "10Gi"},
+		{budget: "requests.hugepages-2Mi.replicas=1Gi", wantQtyType: "requests", wantResource: "hugepages-2Mi", wantBound: 0, wantMax: "1Gi"},
+		{budget: "limits.nvidia.com/gpu=4", wantQtyType: "limits", wantResource: "nvidia.com/gpu", wantBound: -1, wantMax: "4"},
+		{budget: "limits.nvidia.com/gpu.max=8", wantQtyType: "limits", wantResource: "nvidia.com/gpu", wantBound: 2, wantMax: "8"},
+		{budget: "requests.cpu.min=500m", wantQtyType: "requests", wantResource: v1.ResourceCPU, wantBound: 1, wantMax: "500m"},
+		{budget: "requests.cpu.median=1", wantErr: true}, // unknown bound ends up in the resource name
+		{budget: "limits.nvidia.com/mig-1g.5gb.peak=7", wantQtyType: "limits", wantResource: "nvidia.com/mig-1g.5gb", wantBound: 3, wantMax: "7"},
+		{budget: "limits.nvidia.com/mig-1g.5gb=7", wantQtyType: "limits", wantResource: "nvidia.com/mig-1g.5gb", wantBound: -1, wantMax: "7"},
+		// extended resources can have dots in their name, so anything after one which isn't a bound is part of the name
+		{budget: "requests.nvidia.com/gpu.p99=1", wantQtyType: "requests", wantResource: "nvidia.com/gpu.p99", wantBound: -1, wantMax: "1"},
+		{budget: "requests.cpus=1", wantErr: true}, // typo of a resource
+		{budget: "requests.gpu=1", wantErr: true},  // extended resources need their domain
+		{budget: "requests.hugepages-=1", wantErr: true},
+		{budget: "requests.cpu=20 cores", wantErr: true},
+		{budget: "requests.cpu=", wantErr: true},
+		{budget: "requests.cpu", wantErr: true},
+		{budget: "reqs.cpu=1", wantErr: true},
+		{budget: "requests=1", wantErr: true},
+		{budget: "requests.=1", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.budget, func(t *testing.T) {
+			got, err := parseBudget(test.budget)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseBudget() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if got.QtyType != test.wantQtyType || got.Resource != test.wantResource || got.Bound != test.wantBound {
+				t.Errorf("parseBudget() = %s.%s bound %d, want %s.%s bound %d", got.QtyType, got.Resource, got.Bound, test.wantQtyType, test.wantResource, test.wantBound)
+			}
+			if got.Max.Cmp(resource.MustParse(test.wantMax)) != 0 {
+				t.Errorf("parseBudget() max = %s, want %s", got.Max.String(), test.wantMax)
+			}
+		})
+	}
+}
+
+func TestLoadBudgetPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    []string // <qtyType>.<resource>.<bound index>=<max>, in key order
+		wantErr bool
+	}{
+		{
+			name:   "bounds, dotted resources & plain numbers",
+			policy: "budgets:\n  requests.cpu.max: 20\n  limits.memory: 64Gi\n  limits.nvidia.com/gpu.peak: \"4\"\n",
+			want:   []string{"limits.memory.-1=64Gi", "limits.nvidia.com/gpu.3=4", "requests.cpu.2=20"},
+		},
+		{name: "no budgets", policy: "budgets: {}\n"},
+		{name: "unknown resource", policy: "budgets:\n  requests.cpus: 20\n", wantErr: true},
+		{name: "unknown bound", policy: "budgets:\n  requests.cpu.avg: 20\n", wantErr: true},
+		{name: "malformed quantity", policy: "budgets:\n  requests.cpu: twenty\n", wantErr: true},
+		{name: "unknown top level field", policy: "budget:\n  requests.cpu: 20\n", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(path, []byte(test.policy), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			budgets, err := loadBudgetPolicy(path)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("loadBudgetPolicy() error = %v, wantErr %v", err, test.wantErr)
+			}
+			var got []string
+			for _, budget := range budgets {
+				got = append(got, budget.QtyType+"."+string(budget.Resource)+"."+strconv.Itoa(budget.Bound)+"="+budget.Max.String())
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("loadBudgetPolicy() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if _, err := loadBudgetPolicy(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("loadBudgetPolicy() of a missing file should fail")
+	}
+}
+
+func TestCheckBudgets(t *testing.T) {
+	qty := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+	renderData := &AllObjDetail{GrossTotalResources: ResourceTotals{
+		Requests: map[v1.ResourceName]Bounds{
+			v1.ResourceCPU: {qty("4"), qty("4"), qty("10"), qty("12")},
+			// nothing known but the replicas, eg: only DaemonSets without a --nodes range
+			v1.ResourceMemory: {qty("8Gi"), nil, nil, nil},
+		},
+		Limits: map[v1.ResourceName]Bounds{
+			"nvidia.com/gpu": {qty("2"), qty("2"), qty("2"), qty("3")},
+		},
+	}}
+	budget := func(s string) Budget {
+		b, err := parseBudget(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name   string
+		budget string
+		want   []string
+	}{
+		{name: "under budget", budget: "requests.cpu=12", want: nil},
+		{name: "a single bound over", budget: "requests.cpu.max=8", want: []string{"requests.cpu.max is 10, over the budget of 8"}},
+		{name: "a single bound under", budget: "requests.cpu.replicas=8", want: nil},
+		{name: "every bound", budget: "requests.cpu=5", want: []string{"requests.cpu.max is 10, over the budget of 5", "requests.cpu.peak is 12, over the budget of 5"}},
+		{name: "unknown bounds are skipped", budget: "requests.memory=4Gi", want: []string{"requests.memory.replicas is 8Gi, over the budget of 4Gi"}},
+		{name: "unknown bound asked for", budget: "requests.memory.max=1Gi", want: nil},
+		{name: "dotted resource", budget: "limits.nvidia.com/gpu.peak=2", want: []string{"limits.nvidia.com/gpu.peak is 3, over the budget of 2"}},
+		{name: "resource which isn't used", budget: "limits.hugepages-2Mi=0", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := checkBudgets(renderData, []Budget{budget(test.budget)})
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("checkBudgets() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
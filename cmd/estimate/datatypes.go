@@ -1,18 +1,48 @@
 package estimate
 
+import (
+	"sort"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
 // This datastructure collects all the data
 // related to a single input file passed to the tool
 type AllObjDetail struct {
 	Objects             map[string][]*ObjDetail
-	GrossTotalResources [4][3]float32
+	GrossTotalResources ResourceTotals
+	GrossTotalStorage   map[string]Bounds // StorageClass -> [ rep, min, max, peak ]
+	NodeCount           NodeRange         // used as the replica count for DaemonSets
+	DefaultNamespace    string            // namespace of objects which don't set one (same as `helm template -n`)
+	NamespaceTotals     map[string]ResourceTotals
+	NamespaceStorage    map[string]map[string]Bounds // Namespace -> StorageClass -> [ rep, min, max, peak ]
+	QOSTotals           map[v1.PodQOSClass]ResourceTotals
+	RuntimeClasses      map[string]v1.ResourceList // RuntimeClass name -> overhead.podFixed
+
+	currentSource string // input being processed right now, recorded on the objects found in it
 }
 
-func (a *AllObjDetail) chkIfObjAdded(targetObjKind string, targetObjName string) *ObjDetail {
+// No. of nodes the DaemonSet pods are expected to land on.
+// Either a fixed count (Min == Max) or a range (eg: an autoscaled nodepool).
+// Both are -1 when the user hasn't told us anything about the cluster.
+type NodeRange struct {
+	Min int32
+	Max int32
+}
+
+// Objects are identified by namespace, kind & name, so that
+// same named objects rendered into different namespaces (eg: by 2 subcharts) don't collide
+func (a *AllObjDetail) chkIfObjAdded(targetObjNamespace string, targetObjKind string, targetObjName string) *ObjDetail {
 
 	objects, exists := a.Objects[targetObjKind]
 	if exists {
 		for _, computedObj := range objects {
-			if computedObj.ObjName == targetObjName {
+			if computedObj.ObjName == targetObjName && computedObj.ObjNamespace == targetObjNamespace {
 				return computedObj
 			}
 		}
@@ -22,66 +52,222 @@ func (a *AllObjDetail) chkIfObjAdded(targetObjKind string, targetObjName string)
 	}
 }
 
+// Namespace an object will end up in once applied
+func (a *AllObjDetail) namespaceOf(objMeta metav1.ObjectMeta) string {
+	if objMeta.Namespace != "" {
+		return objMeta.Namespace
+	}
+	return a.DefaultNamespace
+}
+
 func (a *AllObjDetail) computeGrossTotalResources() {
 
-	// TODO(v2): 4 nested loops... uggg.... see if this can be optimised later without too much rabbithole
+	a.GrossTotalResources = newResourceTotals()
+	a.GrossTotalStorage = make(map[string]Bounds)
+	a.NamespaceTotals = make(map[string]ResourceTotals)
+	a.NamespaceStorage = make(map[string]map[string]Bounds)
+	a.QOSTotals = make(map[v1.PodQOSClass]ResourceTotals)
 	for _, k8sobjList := range a.Objects {
 		for _, obj := range k8sobjList {
-			for i, resourceType := range obj.TotalResourceForWholeObj {
-				for j, _ := range resourceType {
-					if obj.TotalResourceForWholeObj[i][j] > 0 {
-						a.GrossTotalResources[i][j] += obj.TotalResourceForWholeObj[i][j]
-					}
-				}
+			addTotals(a.GrossTotalResources.Requests, obj.TotalResourceForWholeObj.Requests)
+			addTotals(a.GrossTotalResources.Limits, obj.TotalResourceForWholeObj.Limits)
+			addTotals(a.GrossTotalStorage, obj.TotalStorage)
+
+			if _, exists := a.NamespaceTotals[obj.ObjNamespace]; !exists {
+				a.NamespaceTotals[obj.ObjNamespace] = newResourceTotals()
+				a.NamespaceStorage[obj.ObjNamespace] = make(map[string]Bounds)
+			}
+			addTotals(a.NamespaceTotals[obj.ObjNamespace].Requests, obj.TotalResourceForWholeObj.Requests)
+			addTotals(a.NamespaceTotals[obj.ObjNamespace].Limits, obj.TotalResourceForWholeObj.Limits)
+			addTotals(a.NamespaceStorage[obj.ObjNamespace], obj.TotalStorage)
+
+			// PVCs & HPA targets missing from the manifest don't run any pods
+			if obj.QOSClass == "" {
+				continue
+			}
+			if _, exists := a.QOSTotals[obj.QOSClass]; !exists {
+				a.QOSTotals[obj.QOSClass] = newResourceTotals()
 			}
+			addTotals(a.QOSTotals[obj.QOSClass].Requests, obj.TotalResourceForWholeObj.Requests)
+			addTotals(a.QOSTotals[obj.QOSClass].Limits, obj.TotalResourceForWholeObj.Limits)
 		}
 	}
 
 	// fmt.Println("Printing GrossTotalResources: ", a.GrossTotalResources)
 }
 
+// Every object ordered by kind, namespace & name, so that
+// reports come out the same way on every run (map iteration order is random)
+func (a *AllObjDetail) SortedObjects() []*ObjDetail {
+	var objects []*ObjDetail
+	for _, k8sobjList := range a.Objects {
+		objects = append(objects, k8sobjList...)
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].ObjKind != objects[j].ObjKind {
+			return objects[i].ObjKind < objects[j].ObjKind
+		}
+		if objects[i].ObjNamespace != objects[j].ObjNamespace {
+			return objects[i].ObjNamespace < objects[j].ObjNamespace
+		}
+		return objects[i].ObjName < objects[j].ObjName
+	})
+	return objects
+}
+
+// Every resource name (cpu, memory, hugepages-2Mi, nvidia.com/gpu, ...) requested or limited
+// by at least one object. cpu & memory always come first since every report shows them,
+// followed by ephemeral-storage & then everything else in alphabetical order.
+func (a *AllObjDetail) ResourceNames() []v1.ResourceName {
+	found := map[v1.ResourceName]bool{}
+	for _, k8sobjList := range a.Objects {
+		for _, obj := range k8sobjList {
+			for name := range obj.Resources.Requests {
+				found[name] = true
+			}
+			for name := range obj.Resources.Limits {
+				found[name] = true
+			}
+		}
+	}
+
+	names := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+	if found[v1.ResourceEphemeralStorage] {
+		names = append(names, v1.ResourceEphemeralStorage)
+	}
+	var others []string
+	for name := range found {
+		if name != v1.ResourceCPU && name != v1.ResourceMemory && name != v1.ResourceEphemeralStorage {
+			others = append(others, string(name))
+		}
+	}
+	sort.Strings(others)
+	for _, name := range others {
+		names = append(names, v1.ResourceName(name))
+	}
+	return names
+}
+
 /////////////////////////////////////////////////////
 
 // This datastructure helps abstract
 // all the details related to a single K8s Object
-// (takes away the pain of passing multiple fields like cpu,mem, replicas etc.) 
+// (takes away the pain of passing multiple fields like cpu,mem, replicas etc.)
 type ObjDetail struct {
 	ObjKind                  string
 	ObjName                  string
-	MemReq                   float32 // TODO: Context is change all of this to float64 such that you can do on-the-fly computation for various values
-	MemLim                   float32
-	CpuReq                   float32
-	CpuLim                   float32
+	ObjNamespace             string
+	Resources                PodResources      // scheduler-effective requests/limits of a single pod
+	QOSClass                 v1.PodQOSClass    // empty for objects without pods
+	EmptyDirDisk             resource.Quantity // sizeLimit of disk backed emptyDirs (counts against the ephemeral-storage limit, isn't added to it)
+	EmptyDirMemory           resource.Quantity // sizeLimit of `medium: Memory` emptyDirs (counts against the memory limit, isn't added to it)
 	Replicas                 int32
 	MinReplicas              int32
 	MaxReplicas              int32
+	MaxSurge                 *intstr.IntOrString // of the rolling update, nil when a rollout doesn't add pods (Recreate, StatefulSets, Jobs etc.)
+	ConcurrentRuns           int32               // most runs of a CronJob active at once, -1 when they can't be bounded & 0 for every other kind
 	HPAPresent               bool
-	TotalResourceForWholeObj [4][3]float32 // Schema: [ [ rep, min, max for cpuReq ] [ rep, min, max for cpuLim ] [ rep, min, max for memReq ] [ rep, min, max for memLim ]  ]
+	HPAName                  string
+	HPABehavior              *autoscalingv2.HorizontalPodAutoscalerBehavior // nil when the HPA relies on the default scaling policies
+	NaiveSum                 PodResources                                   // summed across every container (incl. init containers), irrespective of when they run
+	SidecarResources         PodResources                                   // contributed by native sidecars (already included in Resources)
+	Overhead                 v1.ResourceList                                // pod overhead, set explicitly or by the RuntimeClass (already included in Resources)
+	TotalResourceForWholeObj ResourceTotals                                 // per pod resources multiplied by [ rep, min, max, peak ]
+	Storage                  map[string]resource.Quantity                   // StorageClass -> storage claimed per pod (volumeClaimTemplates & ephemeral volumes) or by the object itself (PVCs)
+	TotalStorage             map[string]Bounds                              // StorageClass -> [ rep, min, max, peak ]
+	PodSpec                  *v1.PodSpec                                    // as found in the manifest, nil for PVCs & for HPA targets which aren't in the manifest
+	CronJobSpec              *batchv1.CronJobSpec                           // schedule & policies of a CronJob, nil for every other kind
+	Annotations              map[string]string                              // of the object itself (not its pod template)
+	HPAAnnotations           map[string]string
+	SourceFile               string // input the object was found in (file path, `stdin`, chart or kustomization directory)
+}
 
+// Requests & limits of a single pod, keyed by the resource name.
+// Besides cpu & memory, this holds anything a container can ask for:
+// ephemeral-storage, hugepages-<size>, nvidia.com/gpu & other device plugin resources.
+//
+// Everything is kept as an exact resource.Quantity & only formatted while rendering,
+// so that big totals don't drift the way floats do.
+type PodResources struct {
+	Requests v1.ResourceList
+	Limits   v1.ResourceList
+}
+
+// A quantity multiplied by each of the replica counts.
+// Schema: [ rep, min, max, peak ]. A bound is nil when its count isn't known (eg: no HPA)
+type Bounds [4]*resource.Quantity
+
+// Same as PodResources, but multiplied by the replica counts
+type ResourceTotals struct {
+	Requests map[v1.ResourceName]Bounds
+	Limits   map[v1.ResourceName]Bounds
+}
+
+func newResourceTotals() ResourceTotals {
+	return ResourceTotals{Requests: map[v1.ResourceName]Bounds{}, Limits: map[v1.ResourceName]Bounds{}}
 }
 
-// Ik this is a hack & i will have to refactor my datatypes to make the whole thing generalisable
-// (especially when I start dealing with Volumes/Disks coz 1 pod can have multiple disks).
-// But that's a problem for future me and right now, I am priotising shipping of v1
 func (obj *ObjDetail) computeTotals() {
 
-	/*
-		Schema: [ [ rep, min, max for cpuReq ] [ rep, min, max for cpuLim ] [ rep, min, max for memReq ] [ rep, min, max for memLim ]  ]
-	*/
+	obj.TotalResourceForWholeObj = newResourceTotals()
+	for name, qty := range obj.Resources.Requests {
+		obj.TotalResourceForWholeObj.Requests[name] = obj.multiplyByReplicas(qty)
+	}
+	for name, qty := range obj.Resources.Limits {
+		obj.TotalResourceForWholeObj.Limits[name] = obj.multiplyByReplicas(qty)
+	}
+
+	obj.TotalStorage = make(map[string]Bounds)
+	for storageClass, size := range obj.Storage {
+		obj.TotalStorage[storageClass] = obj.multiplyByReplicas(size)
+	}
 
-	obj.TotalResourceForWholeObj[0][0] = float32(obj.Replicas) * obj.CpuReq
-	obj.TotalResourceForWholeObj[1][0] = float32(obj.Replicas) * obj.CpuLim
-	obj.TotalResourceForWholeObj[2][0] = float32(obj.Replicas) * obj.MemReq
-	obj.TotalResourceForWholeObj[3][0] = float32(obj.Replicas) * obj.MemLim
+}
 
-	obj.TotalResourceForWholeObj[0][1] = float32(obj.MinReplicas) * obj.CpuReq
-	obj.TotalResourceForWholeObj[1][1] = float32(obj.MinReplicas) * obj.CpuLim
-	obj.TotalResourceForWholeObj[2][1] = float32(obj.MinReplicas) * obj.MemReq
-	obj.TotalResourceForWholeObj[3][1] = float32(obj.MinReplicas) * obj.MemLim
+// Schema: [ rep, min, max, peak ] (see TotalCounts). Counts which aren't known (-1) are left as nil,
+// which gets printed as a placeholder & is left out of the gross totals
+func (obj *ObjDetail) multiplyByReplicas(qty resource.Quantity) Bounds {
+	var result Bounds
+	for i, count := range obj.TotalCounts() {
+		if count < 0 {
+			continue
+		}
+		total := qty.DeepCopy()
+		total.Mul(int64(count))
+		result[i] = &total
+	}
+	return result
+}
 
-	obj.TotalResourceForWholeObj[0][2] = float32(obj.MaxReplicas) * obj.CpuReq
-	obj.TotalResourceForWholeObj[1][2] = float32(obj.MaxReplicas) * obj.CpuLim
-	obj.TotalResourceForWholeObj[2][2] = float32(obj.MaxReplicas) * obj.MemReq
-	obj.TotalResourceForWholeObj[3][2] = float32(obj.MaxReplicas) * obj.MemLim
+// Replica counts the totals get multiplied by. Same as ReplicaCounts, except that a min/max which
+// isn't known (-1, eg: no HPA) falls back to the replica count: an object without an HPA runs the
+// same no. of pods at either end, so it belongs in every one of the totals.
+// Still -1 when even the replica count isn't known (eg: a DaemonSet without --nodes).
+func (obj *ObjDetail) TotalCounts() [4]int32 {
+	counts := obj.ReplicaCounts()
+	for i := 1; i <= 2; i++ {
+		if counts[i] < 0 {
+			counts[i] = obj.Replicas
+		}
+	}
+	return counts
+}
 
+// Adds up [ rep, min, max, peak ] totals, skipping the ones which aren't known
+func addTotals[K comparable](dst map[K]Bounds, src map[K]Bounds) {
+	for key, repMinMax := range src {
+		gross := dst[key]
+		for j := range repMinMax {
+			if repMinMax[j] == nil {
+				continue
+			}
+			if gross[j] == nil {
+				total := repMinMax[j].DeepCopy()
+				gross[j] = &total
+			} else {
+				gross[j].Add(*repMinMax[j])
+			}
+		}
+		dst[key] = gross
+	}
 }
@@ -0,0 +1,215 @@
+package estimate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	yaml "sigs.k8s.io/yaml"
+)
+
+// Streams the documents of a manifest one at a time, so that only a single document
+// is held in memory irrespective of how big the manifest is.
+//
+// Documents are split the way kubectl does it (see documentReader): only on a `---` at the very start
+// of a line, optionally followed by a comment (`--- # Source: ...`), so block scalars containing `---`
+// are left alone. JSON input (one or more concatenated objects) has no `---` to split on, so it's decoded
+// an object at a time straight off the reader instead. Input starting with a `{` which isn't JSON
+// (eg: a YAML flow mapping followed by `---` documents) is split as YAML.
+// Errors are only returned when the input can't be read, a document which can't be parsed is handed
+// over as is & fails later on while being processed.
+func readDocuments(r io.Reader, handle func(doc []byte)) error {
+	buffered := bufio.NewReader(r)
+	first, err := peekFirstByte(buffered)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var input io.Reader = buffered
+	if first == '{' {
+		// what the decoder reads of the first object is kept, so that it can be split as YAML instead
+		recorded := &recorder{}
+		jsonInput := io.TeeReader(buffered, recorded)
+		decoder := json.NewDecoder(jsonInput)
+		var object json.RawMessage
+		if err := decoder.Decode(&object); err == nil {
+			recorded.stop()
+			handle(object)
+			return decodeJSONDocuments(decoder, jsonInput, handle)
+		}
+		input = io.MultiReader(bytes.NewReader(recorded.buf.Bytes()), buffered)
+	}
+
+	reader := newDocumentReader(input)
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		trimmed := bytes.TrimSpace(doc)
+		if len(trimmed) == 0 {
+			continue
+		}
+		if trimmed[0] != '{' {
+			handle(doc)
+			continue
+		}
+		// a JSON document between `---` separators can still hold several objects
+		if err := readJSONDocuments(bytes.NewReader(trimmed), handle); err != nil {
+			return err
+		}
+	}
+}
+
+// Collects whatever gets written to it till it's stopped
+type recorder struct {
+	buf     bytes.Buffer
+	stopped bool
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if !r.stopped {
+		r.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+func (r *recorder) stop() {
+	r.stopped = true
+	r.buf = bytes.Buffer{}
+}
+
+// Splits a YAML stream into documents the same way k8s.io/apimachinery/pkg/util/yaml.YAMLReader
+// (used by kubectl) does: on a `---` at the very start of a line, optionally followed by spaces & a comment.
+// YAMLReader fails on a `---` followed by anything else (eg: `--- foo`) & drops the document before it.
+// Here, such a line starts a document of its own which keeps the line, so that only that document
+// fails (or gets parsed, eg: `--- {kind: Pod}`) while being processed & the rest is still read.
+type documentReader struct {
+	reader  *bufio.Reader
+	pending []byte // line starting the next document, nil when the separator isn't part of it
+}
+
+func newDocumentReader(r io.Reader) *documentReader {
+	return &documentReader{reader: bufio.NewReader(r)}
+}
+
+// Returns the next document, io.EOF once there are none left
+func (d *documentReader) Read() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.Write(d.pending)
+	d.pending = nil
+	for {
+		line, err := d.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		if bytes.HasPrefix(line, []byte(documentSeparator)) {
+			var startsDocument []byte
+			if rest := bytes.TrimSpace(line[len(documentSeparator):]); len(rest) > 0 && rest[0] != '#' {
+				startsDocument = line
+			}
+			if buffer.Len() != 0 {
+				d.pending = startsDocument
+				return buffer.Bytes(), nil
+			}
+			line = startsDocument
+		}
+		buffer.Write(line)
+
+		if errors.Is(err, io.EOF) {
+			if buffer.Len() != 0 {
+				return buffer.Bytes(), nil
+			}
+			return nil, io.EOF
+		}
+	}
+}
+
+const documentSeparator = "---"
+
+// Decodes concatenated JSON objects one at a time. Whatever is left once an object can't be
+// decoded is handed over as a single document, so that it's reported as unparsable while being processed
+func readJSONDocuments(r io.Reader, handle func(doc []byte)) error {
+	return decodeJSONDocuments(json.NewDecoder(r), r, handle)
+}
+
+// Same as readJSONDocuments, for a decoder reading from r
+func decodeJSONDocuments(decoder *json.Decoder, r io.Reader, handle func(doc []byte)) error {
+	for {
+		var object json.RawMessage
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+			rest, readErr := io.ReadAll(io.MultiReader(decoder.Buffered(), r))
+			if readErr != nil {
+				return readErr
+			}
+			handle(rest)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		handle(object)
+	}
+}
+
+// First non whitespace byte of the input, without consuming anything.
+// Input starting with more whitespace than the buffer holds is treated as YAML
+func peekFirstByte(r *bufio.Reader) (byte, error) {
+	for n := 1; n <= r.Size(); n++ {
+		peeked, err := r.Peek(n)
+		if err != nil {
+			return 0, err
+		}
+		if c := peeked[n-1]; c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+// Objects wrapped in a `kind: List` (or one of listKinds) the way `kubectl get -o yaml` prints them.
+// Every item is processed on its own, even if some of them fail.
+func processList(yamlRawdata []byte, computedFileResult *AllObjDetail) error {
+	var list struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := yaml.Unmarshal(yamlRawdata, &list); err != nil {
+		return fmt.Errorf("error unmarshalling yaml data into a List: %w", err)
+	}
+
+	var errs []error
+	for i, item := range list.Items {
+		if err := processEachObject(item, computedFileResult); err != nil {
+			errs = append(errs, fmt.Errorf("item %d of the List: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Typed lists of the kinds which are read, besides the generic `kind: List`. Custom resources which
+// happen to end in List (eg: an AllowList CRD) are objects of their own, so they aren't unwrapped
+var listKinds = map[string]bool{
+	"PodList": true, "DeploymentList": true, "StatefulSetList": true, "DaemonSetList": true, "JobList": true, "CronJobList": true,
+	"HorizontalPodAutoscalerList": true, "PersistentVolumeClaimList": true, "RuntimeClassList": true,
+	"ScaledObjectList": true, "ScaledJobList": true,
+}
+
+func isList(kind string) bool {
+	return kind == "List" || listKinds[kind]
+}
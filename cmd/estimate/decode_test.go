@@ -0,0 +1,166 @@
+package estimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadDocuments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // trimmed documents, in order
+	}{
+		{name: "empty input", input: ""},
+		{name: "only whitespace", input: " \n\t\n"},
+		{name: "single document", input: "kind: Pod\n", want: []string{"kind: Pod"}},
+		{
+			name:  "helm source comments",
+			input: "---\n# Source: chart/templates/a.yaml\nkind: Pod\n--- # Source: chart/templates/b.yaml\nkind: Job\n",
+			want:  []string{"# Source: chart/templates/a.yaml\nkind: Pod", "kind: Job"},
+		},
+		{
+			name:  "empty documents are skipped",
+			input: "---\n---\nkind: Pod\n---\n\n---\n",
+			want:  []string{"kind: Pod"},
+		},
+		{
+			name:  "block scalar containing a separator",
+			input: "kind: ConfigMap\ndata:\n  doc: |\n    ---\n    a: 1\n---\nkind: Pod\n",
+			want:  []string{"kind: ConfigMap\ndata:\n  doc: |\n    ---\n    a: 1", "kind: Pod"},
+		},
+		{
+			name:  "separator followed by text keeps its document",
+			input: "kind: Pod\n--- foo\nb: 2\n---\nkind: Job\n",
+			want:  []string{"kind: Pod", "--- foo\nb: 2", "kind: Job"},
+		},
+		{
+			name:  "no trailing newline",
+			input: "kind: Pod\n---\nkind: Job",
+			want:  []string{"kind: Pod", "kind: Job"},
+		},
+		{
+			name:  "concatenated json",
+			input: `{"kind":"Pod"}{"kind":"Job"}` + "\n" + `{"kind":"Service"}`,
+			want:  []string{`{"kind":"Pod"}`, `{"kind":"Job"}`, `{"kind":"Service"}`},
+		},
+		{
+			name:  "json followed by garbage",
+			input: `{"kind":"Pod"}` + "\n" + `{"kind": oops}` + "\n" + `{"kind":"Job"}`,
+			want:  []string{`{"kind":"Pod"}`, `{"kind": oops}` + "\n" + `{"kind":"Job"}`},
+		},
+		{
+			name:  "yaml flow mapping followed by documents",
+			input: "{kind: Pod}\n---\nkind: Job\n",
+			want:  []string{"{kind: Pod}", "kind: Job"},
+		},
+		{
+			name:  "json document holding several objects",
+			input: "kind: Pod\n---\n{\"kind\":\"Job\"}\n{\"kind\":\"Service\"}\n---\nkind: Secret\n",
+			want:  []string{"kind: Pod", `{"kind":"Job"}`, `{"kind":"Service"}`, "kind: Secret"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			err := readDocuments(strings.NewReader(test.input), func(doc []byte) {
+				got = append(got, strings.TrimSpace(string(doc)))
+			})
+			if err != nil {
+				t.Fatalf("readDocuments() error = %v", err)
+			}
+			if strings.Join(got, "\n|\n") != strings.Join(test.want, "\n|\n") {
+				t.Errorf("readDocuments() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsList(t *testing.T) {
+	tests := []struct {
+		kind string
+		want bool
+	}{
+		{kind: "List", want: true},
+		{kind: "DeploymentList", want: true},
+		{kind: "PodList", want: true},
+		{kind: "ScaledObjectList", want: true},
+		{kind: "Deployment", want: false},
+		{kind: "AllowList", want: false},
+		{kind: "FooList", want: false},
+		{kind: "list", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.kind, func(t *testing.T) {
+			if got := isList(test.kind); got != test.want {
+				t.Errorf("isList() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestProcessList(t *testing.T) {
+	deployment := `
+  - apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: api
+    spec:
+      replicas: 2
+      template:
+        spec:
+          containers:
+          - name: app
+            image: app`
+	pod := `
+  - apiVersion: v1
+    kind: Pod
+    metadata:
+      name: debug
+    spec:
+      containers:
+      - name: shell
+        image: shell`
+	broken := `
+  - apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: broken
+    spec:
+      replicas: many`
+
+	tests := []struct {
+		name    string
+		doc     string
+		want    []string // Kind/name of the objects read
+		wantErr bool
+	}{
+		{name: "generic list", doc: "apiVersion: v1\nkind: List\nitems:" + deployment + pod, want: []string{"Deployment/api", "Pod/debug"}},
+		{name: "typed list", doc: "apiVersion: apps/v1\nkind: DeploymentList\nitems:" + deployment, want: []string{"Deployment/api"}},
+		{name: "empty list", doc: "apiVersion: v1\nkind: List\nitems: []\n"},
+		{name: "custom resource ending in List", doc: "apiVersion: example.com/v1\nkind: FooList\nitems:" + deployment},
+		{name: "failing item", doc: "apiVersion: v1\nkind: List\nitems:" + broken + pod, want: []string{"Pod/debug"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := newAllObjDetail(NodeRange{Min: -1, Max: -1}, "default")
+			err := processEachObject([]byte(test.doc), result)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("processEachObject() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr && !strings.Contains(err.Error(), "item 1 of the List") {
+				t.Errorf("processEachObject() error = %v, want it to name the item", err)
+			}
+			var got []string
+			for _, obj := range result.SortedObjects() {
+				got = append(got, obj.ObjKind+"/"+obj.ObjName)
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("objects = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package estimate
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -11,17 +14,158 @@ var EstimateCmd = &cobra.Command{
 	Use:   "estimate",
 	Short: "Estimate resources required for deploying this helm chart",
 	Long: `This command estimates & prints a tabular summary of resources needed for 
-	deploying/applying a helm chart. Currently, only resources estimated are CPU & RAM.
-	The only types which are parsed & summarised are Deployment, Statefulset, Job and Pod`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Estimate called with verbosity %d for the filepath %s\n", reportVerbosity, manifestPath)
-		ProcessManifest(manifestPath, reportVerbosity)
+	deploying/applying a helm chart. Every resource in the requests/limits is estimated: CPU, memory,
+	ephemeral-storage, hugepages & extended resources (eg: nvidia.com/gpu), along with persistent storage per StorageClass.
+	The workload types which are parsed & summarised are Deployment, Statefulset, DaemonSet, Job, CronJob, Pod & KEDA ScaledJob.
+	HPAs & KEDA ScaledObjects fill in the min/max replicas, standalone PersistentVolumeClaims & RuntimeClasses are taken into account as well.
+	DaemonSets are multiplied by the node count passed via --nodes.
+	Besides rendered manifests (-f), a local chart can be passed via --chart (with --values, --set & --release-name)
+	and/or a kustomization directory via --kustomize. Objects of all the inputs end up in the same report`,
+	SilenceUsage: true, // usage is only printed for invalid flags, not when the manifest is over budget etc.
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nodes, err := ParseNodeRange(nodeCount)
+		if err != nil {
+			return fmt.Errorf("error parsing the --nodes flag: %w", err)
+		}
+		if !isKnownOutputFormat(outputFormat) {
+			return fmt.Errorf("unknown --output format %q, expected one of: %s", outputFormat, strings.Join(outputFormats, ", "))
+		}
+		var parsedBudgets []Budget
+		for _, budget := range budgets {
+			parsed, err := parseBudget(budget)
+			if err != nil {
+				return &ExitError{Code: ExitParseError, Err: fmt.Errorf("error parsing the --budget flag: %w", err)}
+			}
+			parsedBudgets = append(parsedBudgets, parsed)
+		}
+		if policyPath != "" {
+			policyBudgets, err := loadBudgetPolicy(policyPath)
+			if err != nil {
+				return &ExitError{Code: ExitParseError, Err: err}
+			}
+			parsedBudgets = append(parsedBudgets, policyBudgets...)
+		}
+
+		// every input ends up in the same report, rendered.yml is only read when nothing else is passed
+		paths := manifestPaths
+		if len(paths) == 0 && chartPath == "" && kustomizationDir == "" {
+			paths = []string{"rendered.yml"}
+		}
+		fmt.Fprintf(os.Stderr, "Estimate called with verbosity %d for %s\n", reportVerbosity, describeInputs(paths))
+
+		result := newAllObjDetail(nodes, namespace)
+		for _, path := range runtimeClassPaths {
+			if err := result.addRuntimeClassFile(path); err != nil {
+				return &ExitError{Code: ExitParseError, Err: err}
+			}
+		}
+		var skipped int
+		if chartPath != "" {
+			rendered, err := renderChart(ChartOptions{ChartPath: chartPath, ValuesFiles: valuesFiles, SetValues: setValues, ReleaseName: releaseName, Namespace: namespace})
+			if err != nil {
+				return &ExitError{Code: ExitParseError, Err: err}
+			}
+			docsSkipped, err := result.addManifest(bytes.NewReader(rendered), chartPath)
+			skipped += docsSkipped
+			if err != nil {
+				return &ExitError{Code: ExitParseError, Err: err}
+			}
+		}
+		if kustomizationDir != "" {
+			built, err := buildKustomization(kustomizationDir)
+			if err != nil {
+				return &ExitError{Code: ExitParseError, Err: err}
+			}
+			docsSkipped, err := result.addManifest(bytes.NewReader(built), kustomizationDir)
+			skipped += docsSkipped
+			if err != nil {
+				return &ExitError{Code: ExitParseError, Err: err}
+			}
+		}
+		fileSkipped, err := result.addManifestFiles(paths)
+		if err != nil {
+			return &ExitError{Code: ExitParseError, Err: err}
+		}
+		skipped += fileSkipped
+		result.computeAllTotals()
+		parseErr := skippedError(skipped)
+
+		switch outputFormat {
+		case "json":
+			if err := renderJSON(os.Stdout, result); err != nil {
+				return fmt.Errorf("error rendering the JSON report: %w", err)
+			}
+		case "csv", "tsv":
+			separator := ','
+			if outputFormat == "tsv" {
+				separator = '\t'
+			}
+			if err := renderCSV(os.Stdout, result, separator); err != nil {
+				return fmt.Errorf("error rendering the CSV report: %w", err)
+			}
+		case "markdown":
+			if err := renderMarkdown(os.Stdout, result); err != nil {
+				return fmt.Errorf("error rendering the markdown report: %w", err)
+			}
+		default:
+			renderTable(reportVerbosity, result) // print tabular summary
+		}
+
+		// a partial report is still printed, but the totals can't be trusted for a budget check
+		if parseErr != nil {
+			return &ExitError{Code: ExitParseError, Err: parseErr}
+		}
+		if violations := checkBudgets(result, parsedBudgets); len(violations) > 0 {
+			for _, violation := range violations {
+				fmt.Fprintln(os.Stderr, "Over budget:", violation)
+			}
+			return &ExitError{Code: ExitOverBudget, Err: fmt.Errorf("%d total(s) over budget", len(violations))}
+		}
+		return nil
 	},
 }
 
+// For the "Estimate called ..." message
+func describeInputs(paths []string) string {
+	var inputs []string
+	if chartPath != "" {
+		inputs = append(inputs, "the chart "+chartPath)
+	}
+	if kustomizationDir != "" {
+		inputs = append(inputs, "the kustomization "+kustomizationDir)
+	}
+	if len(paths) > 0 {
+		inputs = append(inputs, "the filepath(s) "+strings.Join(paths, ", "))
+	}
+	return strings.Join(inputs, " & ")
+}
+
+// Values accepted by --output
+var outputFormats = []string{"table", "json", "csv", "tsv", "markdown"}
+
+func isKnownOutputFormat(format string) bool {
+	for _, known := range outputFormats {
+		if format == known {
+			return true
+		}
+	}
+	return false
+}
+
 var (
-	reportVerbosity int
-	manifestPath    string
+	reportVerbosity   int
+	manifestPaths     []string
+	nodeCount         string
+	namespace         string
+	outputFormat      string
+	budgets           []string
+	policyPath        string
+	chartPath         string
+	valuesFiles       []string
+	setValues         []string
+	releaseName       string
+	kustomizationDir  string
+	runtimeClassPaths []string
 )
 
 func init() {
@@ -30,9 +174,31 @@ func init() {
 
 	// Cobra supports Persistent Flags which will work for this command
 	// and all subcommands, e.g.:
-	EstimateCmd.PersistentFlags().IntVarP(&reportVerbosity, "verbosity", "v", 0, "Provide the verbosity at which report needs to be printed: \n 0: Just print the Object Name, Kind, CPU (req,lim), Mem (Req, Lim) \n 1: Print things menioned in 0 along with a column mentioning replica count (wherever applicable)")
+	EstimateCmd.PersistentFlags().IntVarP(&reportVerbosity, "verbosity", "v", 0, "Provide the verbosity at which report needs to be printed: \n 0: Just print the Object Name, Kind, CPU (req,lim), Mem (Req, Lim) \n 1: Print things menioned in 0 along with a column mentioning replica count (wherever applicable)\n 2: Print a per pod breakdown comparing the scheduler-effective requests/limits with a naive sum of all containers")
+
+	EstimateCmd.PersistentFlags().StringArrayVarP(&manifestPaths, "filepath", "f", nil, "Provide the path to the rendered manifest file\n(i.e this filel would have output contents of 'helm template <chart path> -f <values-file-path>')\nCan be repeated. Also takes directories (searched recursively for .yaml, .yml & .json files), glob patterns & '-' for stdin.\nDefaults to rendered.yml when neither of -f, --chart or --kustomize is passed\n")
+
+	EstimateCmd.PersistentFlags().StringVar(&nodeCount, "nodes", "", "No. of nodes DaemonSet pods will run on. Either a count (eg: 5) or a range (eg: 3-10).\nIf not provided, DaemonSets are listed but left out of the totals")
+
+	EstimateCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default", "Namespace assumed for objects which don't set one (same as 'helm template -n')")
+
+	EstimateCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Format of the report: table, json, csv, tsv or markdown.\n(everything other than table ignores --verbosity & always has every field, see README for the schema)")
+
+	EstimateCmd.PersistentFlags().StringArrayVar(&budgets, "budget", nil, "Upper limit for one of the gross totals, can be repeated. Exits with 3 when a total is over its budget.\nSyntax: <requests|limits>.<resource>[.<replicas|min|max|peak>]=<quantity> (eg: requests.cpu.max=20, limits.memory=64Gi).\nWithout a bound, every known bound is checked")
+
+	EstimateCmd.PersistentFlags().StringVar(&policyPath, "policy", "", "Path to a YAML file with budgets (same syntax as --budget), eg:\nbudgets:\n  requests.cpu.max: 20\n  limits.memory: 64Gi")
+
+	EstimateCmd.PersistentFlags().StringVar(&chartPath, "chart", "", "Path to a local chart directory or packaged .tgz, rendered in-process instead of reading --filepath.\nDependencies need to be vendored under the chart's charts/ directory (eg: by 'helm dependency build'), nothing is downloaded")
+
+	EstimateCmd.PersistentFlags().StringArrayVar(&valuesFiles, "values", nil, "Values file for --chart, can be repeated (later files take precedence)")
+
+	EstimateCmd.PersistentFlags().StringArrayVar(&setValues, "set", nil, "Value overrides for --chart (eg: --set replicas=3,image.tag=v2), can be repeated")
+
+	EstimateCmd.PersistentFlags().StringVar(&releaseName, "release-name", "release-name", "Release name used while rendering --chart (same default as 'helm template')")
+
+	EstimateCmd.PersistentFlags().StringVar(&kustomizationDir, "kustomize", "", "Path to a kustomization directory (eg: an overlay), built in-process instead of reading --filepath (same as 'kustomize build <dir>')")
 
-	EstimateCmd.PersistentFlags().StringVarP(&manifestPath, "filepath", "f", "rendered.yml", "Provide the path to the rendered manifest file\n(i.e this filel would have output contents of 'helm template <chart path> -f <values-file-path>')\n")
+	EstimateCmd.PersistentFlags().StringArrayVar(&runtimeClassPaths, "runtime-classes", nil, "File with the RuntimeClasses of the cluster (eg: the output of 'kubectl get runtimeclass -o yaml'), can be repeated.\nTheir overhead is added to pods setting a runtimeClassName, same as RuntimeClasses found in the manifest. Nothing else in the file is read")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
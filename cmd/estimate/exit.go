@@ -0,0 +1,30 @@
+package estimate
+
+import "fmt"
+
+// Exit codes of the CLI, so that pipelines can tell the failures apart.
+// Anything else going wrong (eg: an invalid flag) exits with 1.
+// Codes of the other commands carry on from these (lint.ExitLintErrors).
+const (
+	ExitOK         = 0
+	ExitParseError = 2 // the manifest, the policy file or a --budget couldn't be read or parsed
+	ExitOverBudget = 3 // at least one of the totals is above its budget
+)
+
+// Returned by the commands when they need to exit with a specific code.
+// The root command unwraps it & exits with Code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
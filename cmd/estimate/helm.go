@@ -0,0 +1,73 @@
+package estimate
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// Where & how a chart should be rendered (same as the arguments of `helm template`)
+type ChartOptions struct {
+	ChartPath   string   // local chart directory or packaged .tgz
+	ValuesFiles []string // --values, later files take precedence
+	SetValues   []string // --set key=value overrides, applied after the values files
+	ReleaseName string
+	Namespace   string
+}
+
+// Renders a local chart in-process, the same way `helm template` does, & returns the
+// rendered manifest (hooks included, tests left out).
+//
+// Nothing is fetched over the network: dependencies have to be vendored under the chart's
+// `charts/` directory already (eg: by `helm dependency build`), otherwise rendering fails.
+func renderChart(opts ChartOptions) ([]byte, error) {
+	chrt, err := loader.Load(opts.ChartPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the chart %s: %w", opts.ChartPath, err)
+	}
+	if err := action.CheckDependencies(chrt, chrt.Metadata.Dependencies); err != nil {
+		return nil, fmt.Errorf("dependencies of the chart %s aren't vendored under charts/ (run 'helm dependency build'): %w", opts.ChartPath, err)
+	}
+
+	valueOpts := &values.Options{ValueFiles: opts.ValuesFiles, Values: opts.SetValues}
+	vals, err := valueOpts.MergeValues(getter.Providers{}) // no providers, so values files can only be local
+	if err != nil {
+		return nil, fmt.Errorf("error merging the values: %w", err)
+	}
+
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.DryRun = true
+	install.ClientOnly = true // don't talk to a cluster, capabilities are the defaults of the helm SDK
+	install.Replace = true
+	install.ReleaseName = opts.ReleaseName
+	install.Namespace = opts.Namespace
+	rel, err := install.Run(chrt, vals)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering the chart %s: %w", opts.ChartPath, err)
+	}
+
+	var manifest strings.Builder
+	manifest.WriteString(rel.Manifest)
+	for _, hook := range rel.Hooks {
+		if isTestHook(hook) {
+			continue
+		}
+		fmt.Fprintf(&manifest, "\n---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+	return []byte(manifest.String()), nil
+}
+
+// `helm test` pods only run on demand, so they aren't part of what a release needs
+func isTestHook(hook *release.Hook) bool {
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
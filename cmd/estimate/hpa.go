@@ -0,0 +1,98 @@
+package estimate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yaml "sigs.k8s.io/yaml"
+)
+
+// Unmarshals an HPA of any of the served API versions into the autoscaling/v2 shape.
+// autoscaling/v1 only knows about a CPU target, so it's converted field by field.
+// The v2beta versions have the same scaling bounds & behaviour fields as v2
+// (metrics differ, but we don't look at those), so they're unmarshalled as is.
+func decodeHPA(apiVersion string, yamlRawdata []byte) (metav1.ObjectMeta, autoscalingv2.HorizontalPodAutoscalerSpec, error) {
+	switch apiVersion {
+	case "autoscaling/v1":
+		var hpa autoscalingv1.HorizontalPodAutoscaler
+		if err := yaml.Unmarshal(yamlRawdata, &hpa); err != nil {
+			return metav1.ObjectMeta{}, autoscalingv2.HorizontalPodAutoscalerSpec{}, err
+		}
+		return hpa.ObjectMeta, autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				Kind:       hpa.Spec.ScaleTargetRef.Kind,
+				Name:       hpa.Spec.ScaleTargetRef.Name,
+				APIVersion: hpa.Spec.ScaleTargetRef.APIVersion,
+			},
+			MinReplicas: hpa.Spec.MinReplicas,
+			MaxReplicas: hpa.Spec.MaxReplicas,
+		}, nil
+
+	case "autoscaling/v2", "autoscaling/v2beta2", "autoscaling/v2beta1":
+		var hpa autoscalingv2.HorizontalPodAutoscaler
+		if err := yaml.Unmarshal(yamlRawdata, &hpa); err != nil {
+			return metav1.ObjectMeta{}, autoscalingv2.HorizontalPodAutoscalerSpec{}, err
+		}
+		return hpa.ObjectMeta, hpa.Spec, nil
+
+	default:
+		return metav1.ObjectMeta{}, autoscalingv2.HorizontalPodAutoscalerSpec{}, fmt.Errorf("unsupported apiVersion %q for a HorizontalPodAutoscaler", apiVersion)
+	}
+}
+
+// Summarises how fast an HPA is allowed to scale up & down.
+// When the behaviour (or one of its directions) isn't set, the API server's defaults are shown instead:
+//   - scale up: no stabilization window, max(100% of the pods, 4 pods) every 15s
+//   - scale down: 300s stabilization window, 100% of the pods every 15s
+func formatHPABehavior(behavior *autoscalingv2.HorizontalPodAutoscalerBehavior) string {
+	var scaleUp, scaleDown *autoscalingv2.HPAScalingRules
+	if behavior != nil {
+		scaleUp, scaleDown = behavior.ScaleUp, behavior.ScaleDown
+	}
+	return "Up: " + formatScalingRules(scaleUp, 0, "100% or 4 pods / 15s") + "\nDown: " + formatScalingRules(scaleDown, 300, "100% / 15s")
+}
+
+func formatScalingRules(rules *autoscalingv2.HPAScalingRules, defaultWindow int32, defaultPolicies string) string {
+	if rules == nil {
+		return defaultPolicies + ", window " + strconv.Itoa(int(defaultWindow)) + "s (default)"
+	}
+
+	window := defaultWindow
+	if rules.StabilizationWindowSeconds != nil {
+		window = *rules.StabilizationWindowSeconds
+	}
+
+	if rules.SelectPolicy != nil && *rules.SelectPolicy == autoscalingv2.DisabledPolicySelect {
+		return "disabled"
+	}
+
+	policies := defaultPolicies
+	if len(rules.Policies) > 0 {
+		var formatted []string
+		for _, policy := range rules.Policies {
+			value := strconv.Itoa(int(policy.Value))
+			if policy.Type == autoscalingv2.PercentScalingPolicy {
+				value += "%"
+			} else {
+				value += " pods"
+			}
+			formatted = append(formatted, value+" / "+strconv.Itoa(int(policy.PeriodSeconds))+"s")
+		}
+
+		// With multiple policies, the one allowing the biggest change wins unless told otherwise
+		selectPolicy := autoscalingv2.MaxChangePolicySelect
+		if rules.SelectPolicy != nil {
+			selectPolicy = *rules.SelectPolicy
+		}
+		policies = strings.Join(formatted, ", ")
+		if len(formatted) > 1 {
+			policies = string(selectPolicy) + "(" + policies + ")"
+		}
+	}
+
+	return policies + ", window " + strconv.Itoa(int(window)) + "s"
+}
@@ -0,0 +1,110 @@
+package estimate
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Path which stands for stdin, eg: `helm template ... | manresca estimate -f -`
+const stdinPath = "-"
+
+// Reads & processes every manifest the paths point at.
+// Returns the no. of documents which couldn't be parsed, errors are only for inputs which can't be read.
+func (a *AllObjDetail) addManifestFiles(manifestPaths []string) (int, error) {
+	files, err := expandManifestPaths(manifestPaths)
+	if err != nil {
+		return 0, err
+	}
+
+	var skipped int
+	for _, file := range files {
+		fileSkipped, err := a.addManifestFile(file)
+		skipped += fileSkipped
+		if err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+func (a *AllObjDetail) addManifestFile(file string) (int, error) {
+	if file == stdinPath {
+		return a.addManifest(os.Stdin, "stdin")
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, fmt.Errorf("error reading YAML file %s: %w", file, err)
+	}
+	defer f.Close()
+	return a.addManifest(f, file)
+}
+
+// Turns the paths passed via --filepath into a list of files.
+// Directories are searched recursively for .yaml, .yml & .json files (in lexical order),
+// glob patterns are expanded & `-` is kept as is for stdin. A file is only listed once,
+// even if more than one path matches it.
+func expandManifestPaths(manifestPaths []string) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, manifestPath := range manifestPaths {
+		if manifestPath == stdinPath {
+			add(stdinPath)
+			continue
+		}
+
+		matches := []string{manifestPath}
+		if strings.ContainsAny(manifestPath, "*?[") {
+			var err error
+			matches, err = filepath.Glob(manifestPath)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob pattern %s: %w", manifestPath, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match the glob pattern %s", manifestPath)
+			}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("error reading YAML file: %w", err)
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			err = filepath.WalkDir(match, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !entry.IsDir() && isManifestFile(path) {
+					add(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error walking the directory %s: %w", match, err)
+			}
+		}
+	}
+	return files, nil
+}
+
+func isManifestFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
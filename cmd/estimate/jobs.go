@@ -0,0 +1,98 @@
+package estimate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// Limits on the schedule walk of overlappingRuns. A year covers every schedule which repeats
+// at least yearly & schedules which fire often enough to hit the activation limit first
+// (eg: every minute) have repeated their pattern many times over by then.
+const (
+	scheduleWalkHorizon     = 366 * 24 * time.Hour
+	scheduleWalkActivations = 10000
+)
+
+// No. of pods a Job runs at the same time.
+// The controller never keeps more than parallelism (default 1) pods active & never more than
+// the completions still left, so a Job with completions below its parallelism runs only that many.
+// Indexed Jobs (completionMode: Indexed) always have completions, NonIndexed ones without it
+// are work queues where every pod runs till the queue is drained.
+func jobPods(spec batchv1.JobSpec) (int32, error) {
+	var parallelism int32 = 1
+	if spec.Parallelism != nil {
+		parallelism = *spec.Parallelism
+	}
+	if parallelism < 0 {
+		return 0, fmt.Errorf("parallelism (%d) can't be negative", parallelism)
+	}
+	if spec.CompletionMode != nil && *spec.CompletionMode == batchv1.IndexedCompletion && spec.Completions == nil {
+		return 0, fmt.Errorf("completions has to be set for completionMode %s", batchv1.IndexedCompletion)
+	}
+	if spec.Completions != nil && *spec.Completions < parallelism {
+		return *spec.Completions, nil
+	}
+	return parallelism, nil
+}
+
+// Most runs of a CronJob which can be active at once. Only concurrencyPolicy Allow (the default)
+// lets runs overlap: a run lasts at most activeDeadlineSeconds, so the worst case is the most
+// schedule activations falling within any window of that length.
+// Returns -1 when runs can overlap, but there's no deadline to bound them by.
+func overlappingRuns(cronJob batchv1.CronJob) (int32, error) {
+	if cronJob.Spec.ConcurrencyPolicy != "" && cronJob.Spec.ConcurrencyPolicy != batchv1.AllowConcurrent {
+		return 1, nil
+	}
+	schedule, location, err := ParseCronSchedule(cronJob.Spec)
+	if err != nil {
+		return 0, err
+	}
+	deadline := cronJob.Spec.JobTemplate.Spec.ActiveDeadlineSeconds
+	if deadline == nil {
+		return -1, nil
+	}
+	return maxActivationsWithin(schedule, location, time.Duration(*deadline)*time.Second), nil
+}
+
+// Parses the schedule of a CronJob the same way the CronJob controller does (standard 5 field
+// cron syntax & the @hourly style macros) along with the location of its timeZone (UTC if not set).
+func ParseCronSchedule(spec batchv1.CronJobSpec) (cron.Schedule, *time.Location, error) {
+	schedule, err := cron.ParseStandard(spec.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule %q: %w", spec.Schedule, err)
+	}
+	location := time.UTC
+	if spec.TimeZone != nil {
+		location, err = time.LoadLocation(*spec.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unknown timeZone %q: %w", *spec.TimeZone, err)
+		}
+	}
+	return schedule, location, nil
+}
+
+// Slides a window of the given length over the schedule's activations & returns the most
+// activations it ever holds. A run started at t is still active at t+window-1s, so a window
+// holds the activations in [t, t+window).
+func maxActivationsWithin(schedule cron.Schedule, location *time.Location, window time.Duration) int32 {
+	start := time.Date(2000, time.January, 1, 0, 0, 0, 0, location) // fixed, so that the estimate doesn't change from run to run
+	var activations []time.Time
+	var most int32
+	for next := schedule.Next(start); !next.IsZero() && next.Sub(start) < scheduleWalkHorizon && len(activations) < scheduleWalkActivations; next = schedule.Next(next) {
+		activations = append(activations, next)
+	}
+
+	first := 0
+	for last := range activations {
+		for activations[last].Sub(activations[first]) >= window {
+			first++
+		}
+		if count := int32(last - first + 1); count > most {
+			most = count
+		}
+	}
+	return most
+}
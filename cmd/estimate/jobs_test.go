@@ -0,0 +1,133 @@
+package estimate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestJobPods(t *testing.T) {
+	indexed := batchv1.IndexedCompletion
+	tests := []struct {
+		name    string
+		spec    batchv1.JobSpec
+		want    int32
+		wantErr bool
+	}{
+		{name: "defaults to a single pod", spec: batchv1.JobSpec{}, want: 1},
+		{name: "work queue runs parallelism pods", spec: batchv1.JobSpec{Parallelism: int32Ptr(3)}, want: 3},
+		{name: "completions below parallelism", spec: batchv1.JobSpec{Parallelism: int32Ptr(5), Completions: int32Ptr(2)}, want: 2},
+		{name: "completions above parallelism", spec: batchv1.JobSpec{Parallelism: int32Ptr(2), Completions: int32Ptr(10)}, want: 2},
+		{name: "indexed without completions", spec: batchv1.JobSpec{CompletionMode: &indexed}, wantErr: true},
+		{name: "negative parallelism", spec: batchv1.JobSpec{Parallelism: int32Ptr(-1)}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := jobPods(test.spec)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("jobPods() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && got != test.want {
+				t.Errorf("jobPods() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMaxActivationsWithin(t *testing.T) {
+	tests := []struct {
+		schedule string
+		window   time.Duration
+		want     int32
+	}{
+		{schedule: "*/10 * * * *", window: 30 * time.Minute, want: 3},
+		{schedule: "*/10 * * * *", window: 31 * time.Minute, want: 4},
+		{schedule: "0 * * * *", window: 30 * time.Minute, want: 1},
+		{schedule: "0 * * * *", window: time.Hour, want: 1},
+		{schedule: "0,5 * * * *", window: 10 * time.Minute, want: 2},
+		{schedule: "@daily", window: 48 * time.Hour, want: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.schedule+" within "+test.window.String(), func(t *testing.T) {
+			schedule, err := cron.ParseStandard(test.schedule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := maxActivationsWithin(schedule, time.UTC, test.window); got != test.want {
+				t.Errorf("maxActivationsWithin() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestOverlappingRuns(t *testing.T) {
+	cronJob := func(policy batchv1.ConcurrencyPolicy, schedule string, deadline *int64) batchv1.CronJob {
+		return batchv1.CronJob{Spec: batchv1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: policy,
+			JobTemplate:       batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{ActiveDeadlineSeconds: deadline}},
+		}}
+	}
+	tests := []struct {
+		name    string
+		cronJob batchv1.CronJob
+		want    int32
+		wantErr bool
+	}{
+		{name: "allow is the default", cronJob: cronJob("", "*/10 * * * *", int64Ptr(1500)), want: 3},
+		{name: "allow bounded by the deadline", cronJob: cronJob(batchv1.AllowConcurrent, "*/10 * * * *", int64Ptr(1500)), want: 3},
+		{name: "allow without a deadline can't be bounded", cronJob: cronJob(batchv1.AllowConcurrent, "*/10 * * * *", nil), want: -1},
+		{name: "forbid never overlaps", cronJob: cronJob(batchv1.ForbidConcurrent, "*/10 * * * *", nil), want: 1},
+		{name: "replace never overlaps", cronJob: cronJob(batchv1.ReplaceConcurrent, "*/10 * * * *", nil), want: 1},
+		{name: "invalid schedule", cronJob: cronJob(batchv1.AllowConcurrent, "every minute", int64Ptr(60)), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := overlappingRuns(test.cronJob)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("overlappingRuns() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && got != test.want {
+				t.Errorf("overlappingRuns() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCronJobPeakReplicas(t *testing.T) {
+	tests := []struct {
+		name           string
+		replicas       int32
+		concurrentRuns int32
+		want           int32
+	}{
+		{name: "non overlapping runs", replicas: 2, concurrentRuns: 1, want: 2},
+		{name: "pods of every overlapping run", replicas: 2, concurrentRuns: 3, want: 6},
+		{name: "unbounded overlap isn't known", replicas: 2, concurrentRuns: -1, want: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := ObjDetail{ObjKind: "CronJob", Replicas: test.replicas, MinReplicas: -1, MaxReplicas: -1, ConcurrentRuns: test.concurrentRuns}
+			if got := obj.PeakReplicas(); got != test.want {
+				t.Errorf("PeakReplicas() = %d, want %d", got, test.want)
+			}
+			if counts := obj.TotalCounts(); counts[1] != test.replicas || counts[2] != test.replicas {
+				t.Errorf("TotalCounts() min/max = %d/%d, want the replica count %d", counts[1], counts[2], test.replicas)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package estimate
+
+import (
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yaml "sigs.k8s.io/yaml"
+)
+
+// KEDA's defaults (keda.sh/v1alpha1) for the replica counts which aren't set
+const (
+	kedaDefaultMinReplicas int32 = 0
+	kedaDefaultMaxReplicas int32 = 100
+)
+
+// Only the fields of a keda.sh/v1alpha1 ScaledObject which affect the replica counts,
+// so that we don't have to depend on the KEDA module
+type scaledObject struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		ScaleTargetRef struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Name       string `json:"name"`
+		} `json:"scaleTargetRef"`
+		MinReplicaCount  *int32 `json:"minReplicaCount"`
+		MaxReplicaCount  *int32 `json:"maxReplicaCount"`
+		IdleReplicaCount *int32 `json:"idleReplicaCount"`
+		Advanced         *struct {
+			HorizontalPodAutoscalerConfig *struct {
+				Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior"`
+			} `json:"horizontalPodAutoscalerConfig"`
+		} `json:"advanced"`
+	} `json:"spec"`
+}
+
+// Same as scaledObject, for a keda.sh/v1alpha1 ScaledJob
+type scaledJob struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		JobTargetRef    batchv1.JobSpec `json:"jobTargetRef"`
+		MinReplicaCount *int32          `json:"minReplicaCount"`
+		MaxReplicaCount *int32          `json:"maxReplicaCount"`
+	} `json:"spec"`
+}
+
+// A ScaledObject is turned into the HPA KEDA creates for it, so that it goes through processHPASpec
+// (it shows up under its own name wherever the HPA is shown).
+// The lowest count is idleReplicaCount when it's set (the target idles there while no trigger is active),
+// minReplicaCount otherwise. scaleTargetRef defaults to a Deployment, same as KEDA.
+func decodeScaledObject(yamlRawdata []byte) (metav1.ObjectMeta, autoscalingv2.HorizontalPodAutoscalerSpec, error) {
+	var so scaledObject
+	if err := yaml.Unmarshal(yamlRawdata, &so); err != nil {
+		return metav1.ObjectMeta{}, autoscalingv2.HorizontalPodAutoscalerSpec{}, err
+	}
+
+	minReplicas, maxReplicas := kedaDefaultMinReplicas, kedaDefaultMaxReplicas
+	if so.Spec.MinReplicaCount != nil {
+		minReplicas = *so.Spec.MinReplicaCount
+	}
+	if so.Spec.MaxReplicaCount != nil {
+		maxReplicas = *so.Spec.MaxReplicaCount
+	}
+	if so.Spec.IdleReplicaCount != nil {
+		if *so.Spec.IdleReplicaCount >= minReplicas {
+			return metav1.ObjectMeta{}, autoscalingv2.HorizontalPodAutoscalerSpec{}, fmt.Errorf("idleReplicaCount (%d) has to be less than minReplicaCount (%d)", *so.Spec.IdleReplicaCount, minReplicas)
+		}
+		minReplicas = *so.Spec.IdleReplicaCount
+	}
+
+	kind := so.Spec.ScaleTargetRef.Kind
+	if kind == "" {
+		kind = "Deployment"
+	}
+
+	var behavior *autoscalingv2.HorizontalPodAutoscalerBehavior
+	if so.Spec.Advanced != nil && so.Spec.Advanced.HorizontalPodAutoscalerConfig != nil {
+		behavior = so.Spec.Advanced.HorizontalPodAutoscalerConfig.Behavior
+	}
+
+	return so.ObjectMeta, autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			Kind:       kind,
+			Name:       so.Spec.ScaleTargetRef.Name,
+			APIVersion: so.Spec.ScaleTargetRef.APIVersion,
+		},
+		MinReplicas: &minReplicas,
+		MaxReplicas: maxReplicas,
+		Behavior:    behavior,
+	}, nil
+}
+
+// A ScaledJob creates a Job per event, up to maxReplicaCount of them at a time, & keeps
+// minReplicaCount Jobs around while idle. Every Job runs as many pods as jobPods says,
+// so the min/max columns hold the job pods which can be running at once.
+func processScaledJob(yamlRawdata []byte, computedFileResult *AllObjDetail) error {
+	var sj scaledJob
+	if err := yaml.Unmarshal(yamlRawdata, &sj); err != nil {
+		return fmt.Errorf("error unmarshalling yaml data into ScaledJob struct type: %w", err)
+	}
+
+	minJobs, maxJobs := kedaDefaultMinReplicas, kedaDefaultMaxReplicas
+	if sj.Spec.MinReplicaCount != nil {
+		minJobs = *sj.Spec.MinReplicaCount
+	}
+	if sj.Spec.MaxReplicaCount != nil {
+		maxJobs = *sj.Spec.MaxReplicaCount
+	}
+	if maxJobs < minJobs {
+		return fmt.Errorf("maxReplicaCount (%d) is less than minReplicaCount (%d)", maxJobs, minJobs)
+	}
+	podsPerJob, err := jobPods(sj.Spec.JobTargetRef)
+	if err != nil {
+		return fmt.Errorf("invalid jobTargetRef of ScaledJob %s: %w", sj.Name, err)
+	}
+
+	namespace := computedFileResult.namespaceOf(sj.ObjectMeta)
+	if err := processPodSpec(sj.Spec.JobTargetRef.Template.Spec, sj.Name, namespace, "ScaledJob", -1, sj.Annotations, computedFileResult); err != nil {
+		return fmt.Errorf("unable to process spec for kind ScaledJob and object %s: %w", sj.Name, err)
+	}
+	computedObj := computedFileResult.chkIfObjAdded(namespace, "ScaledJob", sj.Name)
+	computedObj.MinReplicas = minJobs * podsPerJob
+	computedObj.MaxReplicas = maxJobs * podsPerJob
+	return nil
+}
@@ -0,0 +1,167 @@
+package estimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeScaledObject(t *testing.T) {
+	scaledObject := func(spec string) []byte {
+		return []byte("apiVersion: keda.sh/v1alpha1\nkind: ScaledObject\nmetadata:\n  name: worker-scaler\nspec:\n" + spec)
+	}
+	tests := []struct {
+		name         string
+		spec         string
+		wantKind     string
+		wantMin      int32
+		wantMax      int32
+		wantBehavior bool
+		wantErr      bool
+	}{
+		{name: "defaults", spec: "  scaleTargetRef:\n    name: worker\n", wantKind: "Deployment", wantMin: 0, wantMax: 100},
+		{name: "min & max", spec: "  scaleTargetRef:\n    name: worker\n  minReplicaCount: 2\n  maxReplicaCount: 20\n", wantKind: "Deployment", wantMin: 2, wantMax: 20},
+		{name: "idle replaces min", spec: "  scaleTargetRef:\n    name: worker\n  idleReplicaCount: 0\n  minReplicaCount: 3\n  maxReplicaCount: 10\n", wantKind: "Deployment", wantMin: 0, wantMax: 10},
+		{name: "idle equal to min", spec: "  scaleTargetRef:\n    name: worker\n  idleReplicaCount: 2\n  minReplicaCount: 2\n", wantErr: true},
+		{name: "idle with the default min", spec: "  scaleTargetRef:\n    name: worker\n  idleReplicaCount: 0\n", wantErr: true},
+		{name: "statefulset target", spec: "  scaleTargetRef:\n    apiVersion: apps/v1\n    kind: StatefulSet\n    name: worker\n  maxReplicaCount: 5\n", wantKind: "StatefulSet", wantMin: 0, wantMax: 5},
+		{name: "custom resource target", spec: "  scaleTargetRef:\n    apiVersion: argoproj.io/v1alpha1\n    kind: Rollout\n    name: worker\n", wantKind: "Rollout", wantMin: 0, wantMax: 100},
+		{
+			name:         "hpa behavior",
+			spec:         "  scaleTargetRef:\n    name: worker\n  advanced:\n    horizontalPodAutoscalerConfig:\n      behavior:\n        scaleUp:\n          stabilizationWindowSeconds: 60\n",
+			wantKind:     "Deployment",
+			wantMin:      0,
+			wantMax:      100,
+			wantBehavior: true,
+		},
+		{name: "not a number", spec: "  maxReplicaCount: lots\n", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta, spec, err := decodeScaledObject(scaledObject(test.spec))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("decodeScaledObject() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if meta.Name != "worker-scaler" {
+				t.Errorf("decodeScaledObject() name = %s, want worker-scaler", meta.Name)
+			}
+			if spec.ScaleTargetRef.Kind != test.wantKind || spec.ScaleTargetRef.Name != "worker" {
+				t.Errorf("decodeScaledObject() target = %s/%s, want %s/worker", spec.ScaleTargetRef.Kind, spec.ScaleTargetRef.Name, test.wantKind)
+			}
+			if *spec.MinReplicas != test.wantMin || spec.MaxReplicas != test.wantMax {
+				t.Errorf("decodeScaledObject() = %d-%d, want %d-%d", *spec.MinReplicas, spec.MaxReplicas, test.wantMin, test.wantMax)
+			}
+			if (spec.Behavior != nil) != test.wantBehavior {
+				t.Errorf("decodeScaledObject() behavior = %v, want it set %v", spec.Behavior, test.wantBehavior)
+			}
+		})
+	}
+}
+
+func TestScaledObjectTarget(t *testing.T) {
+	manifest := `
+apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: db-scaler
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: StatefulSet
+    name: db
+  idleReplicaCount: 0
+  minReplicaCount: 1
+  maxReplicaCount: 4
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: db
+        image: db
+`
+	result := newAllObjDetail(NodeRange{Min: -1, Max: -1}, "default")
+	if err := readDocuments(strings.NewReader(manifest), func(doc []byte) {
+		if err := processEachObject(doc, result); err != nil {
+			t.Fatal(err)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := result.chkIfObjAdded("default", "StatefulSet", "db")
+	if obj == nil {
+		t.Fatal("the StatefulSet isn't in the result")
+	}
+	if got, want := obj.ReplicaCounts(), [4]int32{2, 0, 4, 4}; got != want {
+		t.Errorf("ReplicaCounts() = %v, want %v", got, want)
+	}
+	if obj.HPAName != "db-scaler" {
+		t.Errorf("HPAName = %s, want db-scaler", obj.HPAName)
+	}
+	if result.chkIfObjAdded("default", "Deployment", "db") != nil {
+		t.Error("the ScaledObject shouldn't have targeted a Deployment")
+	}
+}
+
+func TestProcessScaledJob(t *testing.T) {
+	scaledJob := func(spec string, jobSpec string) []byte {
+		return []byte(`
+apiVersion: keda.sh/v1alpha1
+kind: ScaledJob
+metadata:
+  name: queue-worker
+spec:
+` + spec + `
+  jobTargetRef:
+` + jobSpec + `
+    template:
+      spec:
+        containers:
+        - name: worker
+          image: worker
+`)
+	}
+	tests := []struct {
+		name    string
+		spec    string
+		jobSpec string
+		want    [4]int32
+		wantErr bool
+	}{
+		{name: "defaults", want: [4]int32{-1, 0, 100, 100}},
+		{name: "min & max", spec: "  minReplicaCount: 1\n  maxReplicaCount: 5", want: [4]int32{-1, 1, 5, 5}},
+		{name: "max times parallelism", spec: "  minReplicaCount: 1\n  maxReplicaCount: 5", jobSpec: "    parallelism: 3", want: [4]int32{-1, 3, 15, 15}},
+		{name: "completions below parallelism", spec: "  maxReplicaCount: 4", jobSpec: "    parallelism: 3\n    completions: 2", want: [4]int32{-1, 0, 8, 8}},
+		{name: "max below min", spec: "  minReplicaCount: 5\n  maxReplicaCount: 2", wantErr: true},
+		{name: "invalid job", jobSpec: "    parallelism: -1", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := newAllObjDetail(NodeRange{Min: -1, Max: -1}, "default")
+			err := processScaledJob(scaledJob(test.spec, test.jobSpec), result)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("processScaledJob() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			obj := result.chkIfObjAdded("default", "ScaledJob", "queue-worker")
+			if obj == nil {
+				t.Fatal("the ScaledJob isn't in the result")
+			}
+			if got := obj.ReplicaCounts(); got != test.want {
+				t.Errorf("ReplicaCounts() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
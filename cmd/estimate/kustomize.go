@@ -0,0 +1,24 @@
+package estimate
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// Builds a kustomization directory in-process, the same way `kustomize build <dir>` does,
+// & returns the resulting objects as a multi-document manifest.
+// Patches to replicas or resources are applied by then, so they show up in the estimate as is.
+func buildKustomization(kustomizationDir string) ([]byte, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := kustomizer.Run(filesys.MakeFsOnDisk(), kustomizationDir)
+	if err != nil {
+		return nil, fmt.Errorf("error building the kustomization %s: %w", kustomizationDir, err)
+	}
+	manifest, err := resMap.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("error converting the kustomization %s to YAML: %w", kustomizationDir, err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,175 @@
+package estimate
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Computes the requests & limits of a pod the way the scheduler sees them
+// (refer `PodRequests` / `PodLimits` in k8s.io/component-helpers/resource):
+//
+//	max(largest init container, sum of app containers) + pod overhead
+//
+// Init containers run one after the other & are done before the app containers start,
+// so only the biggest one of them matters. Limits follow the same rule.
+//
+// Native sidecars (init containers with `restartPolicy: Always`) are the exception:
+// they keep running for the whole life of the pod, so they are added to the app containers.
+// They also start before the init containers which follow them, so every such init
+// container runs alongside all the sidecars declared before it.
+func effectivePodResources(podSpec v1.PodSpec) (v1.ResourceList, v1.ResourceList) {
+	requests, limits := v1.ResourceList{}, v1.ResourceList{}
+	for _, container := range podSpec.Containers {
+		addResourceList(requests, containerRequests(container))
+		addResourceList(limits, container.Resources.Limits)
+	}
+
+	initRequests, initLimits := v1.ResourceList{}, v1.ResourceList{}
+	sidecarRequests, sidecarLimits := v1.ResourceList{}, v1.ResourceList{}
+	for _, container := range podSpec.InitContainers {
+		containerReqs, containerLims := v1.ResourceList{}, v1.ResourceList{}
+		if isSidecar(container) {
+			addResourceList(requests, containerRequests(container))
+			addResourceList(limits, container.Resources.Limits)
+			addResourceList(sidecarRequests, containerRequests(container))
+			addResourceList(sidecarLimits, container.Resources.Limits)
+		} else {
+			addResourceList(containerReqs, containerRequests(container))
+			addResourceList(containerLims, container.Resources.Limits)
+		}
+		addResourceList(containerReqs, sidecarRequests)
+		addResourceList(containerLims, sidecarLimits)
+
+		maxResourceList(initRequests, containerReqs)
+		maxResourceList(initLimits, containerLims)
+	}
+	maxResourceList(requests, initRequests)
+	maxResourceList(limits, initLimits)
+
+	addPodOverhead(requests, limits, podSpec.Overhead)
+	return requests, limits
+}
+
+// Overhead is always added to requests, but only added to a limit
+// if the pod actually sets a limit for that resource (same as the kubelet)
+func addPodOverhead(requests v1.ResourceList, limits v1.ResourceList, overhead v1.ResourceList) {
+	addResourceList(requests, overhead)
+	for name, qty := range overhead {
+		if limit, exists := limits[name]; exists {
+			limit.Add(qty)
+			limits[name] = limit
+		}
+	}
+}
+
+// Sums up requests & limits of just the native sidecars of a pod
+// (these are already part of what `effectivePodResources` returns)
+func sidecarPodResources(podSpec v1.PodSpec) (v1.ResourceList, v1.ResourceList) {
+	requests, limits := v1.ResourceList{}, v1.ResourceList{}
+	for _, container := range podSpec.InitContainers {
+		if isSidecar(container) {
+			addResourceList(requests, containerRequests(container))
+			addResourceList(limits, container.Resources.Limits)
+		}
+	}
+	return requests, limits
+}
+
+// Native sidecars (k8s 1.29+) are init containers which are restarted
+// for the whole life of the pod instead of running to completion
+func isSidecar(container v1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
+// The way earlier versions of this tool added things up:
+// every container (init or not) is summed up irrespective of when it runs.
+// Handy as an upper bound & to compare against older reports.
+func naivePodResources(podSpec v1.PodSpec) (v1.ResourceList, v1.ResourceList) {
+	requests, limits := v1.ResourceList{}, v1.ResourceList{}
+	for _, container := range append(append([]v1.Container{}, podSpec.Containers...), podSpec.InitContainers...) {
+		addResourceList(requests, container.Resources.Requests)
+		addResourceList(limits, container.Resources.Limits)
+	}
+	return requests, limits
+}
+
+// Adds up `sizeLimit` of the emptyDir volumes of a pod.
+// Disk backed ones eat into the node's ephemeral storage, whereas `medium: Memory`
+// ones are tmpfs mounts which are charged against the pod's memory.
+// emptyDirs without a sizeLimit are unbounded, so there's nothing to add for them.
+func emptyDirSizeLimits(podSpec v1.PodSpec) (disk resource.Quantity, memory resource.Quantity) {
+	for _, volume := range podSpec.Volumes {
+		if volume.EmptyDir == nil || volume.EmptyDir.SizeLimit == nil {
+			continue
+		}
+		if volume.EmptyDir.Medium == v1.StorageMediumMemory {
+			memory.Add(*volume.EmptyDir.SizeLimit)
+		} else {
+			disk.Add(*volume.EmptyDir.SizeLimit)
+		}
+	}
+	return disk, memory
+}
+
+// When a container only sets a limit for a resource, the API server defaults
+// its request to that limit. Rendered manifests haven't gone through the
+// API server yet, so we do the defaulting ourselves.
+func containerRequests(container v1.Container) v1.ResourceList {
+	requests := container.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = v1.ResourceList{}
+	}
+	for name, limit := range container.Resources.Limits {
+		if _, exists := requests[name]; !exists {
+			requests[name] = limit.DeepCopy()
+		}
+	}
+	return requests
+}
+
+// QoS class the kubelet assigns to a pod (refer `GetPodQOS` in k8s.io/kubernetes/pkg/apis/core/v1/helper/qos).
+// Only cpu & memory count, requests are defaulted from the limits first (same as the API server):
+//   - BestEffort: no container requests or limits any cpu/memory
+//   - Guaranteed: every container (incl. init containers) limits both cpu & memory, and the requests equal the limits
+//   - Burstable: everything else
+//
+// BestEffort pods are the first to be evicted under node pressure, followed by Burstable ones
+// using more than their requests.
+func qosClass(podSpec v1.PodSpec) v1.PodQOSClass {
+	requests, limits := v1.ResourceList{}, v1.ResourceList{}
+	isGuaranteed := true
+	for _, container := range append(append([]v1.Container{}, podSpec.Containers...), podSpec.InitContainers...) {
+		for name, qty := range containerRequests(container) {
+			if isQOSResource(name) && qty.Sign() > 0 {
+				addResourceList(requests, v1.ResourceList{name: qty})
+			}
+		}
+		limitsFound := 0
+		for name, qty := range container.Resources.Limits {
+			if isQOSResource(name) && qty.Sign() > 0 {
+				addResourceList(limits, v1.ResourceList{name: qty})
+				limitsFound++
+			}
+		}
+		if limitsFound != 2 {
+			isGuaranteed = false
+		}
+	}
+
+	if len(requests) == 0 && len(limits) == 0 {
+		return v1.PodQOSBestEffort
+	}
+	for name, request := range requests {
+		if limit, exists := limits[name]; !exists || limit.Cmp(request) != 0 {
+			isGuaranteed = false
+		}
+	}
+	if isGuaranteed && len(requests) == len(limits) {
+		return v1.PodQOSGuaranteed
+	}
+	return v1.PodQOSBurstable
+}
+
+func isQOSResource(name v1.ResourceName) bool {
+	return name == v1.ResourceCPU || name == v1.ResourceMemory
+}
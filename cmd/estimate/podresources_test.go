@@ -0,0 +1,192 @@
+package estimate
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func container(name string, requests v1.ResourceList, limits v1.ResourceList) v1.Container {
+	return v1.Container{Name: name, Resources: v1.ResourceRequirements{Requests: requests, Limits: limits}}
+}
+
+func sidecar(name string, requests v1.ResourceList, limits v1.ResourceList) v1.Container {
+	always := v1.ContainerRestartPolicyAlways
+	result := container(name, requests, limits)
+	result.RestartPolicy = &always
+	return result
+}
+
+func cpu(qty string) v1.ResourceList {
+	return v1.ResourceList{v1.ResourceCPU: resource.MustParse(qty)}
+}
+
+func cpuMemory(cpu string, memory string) v1.ResourceList {
+	return v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse(memory)}
+}
+
+func equalResourceLists(a v1.ResourceList, b v1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, qty := range a {
+		other, exists := b[name]
+		if !exists || qty.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEffectivePodResources(t *testing.T) {
+	tests := []struct {
+		name         string
+		podSpec      v1.PodSpec
+		wantRequests v1.ResourceList
+		wantLimits   v1.ResourceList
+	}{
+		{
+			name: "biggest init container wins over the app containers",
+			podSpec: v1.PodSpec{
+				InitContainers: []v1.Container{container("migrate", cpu("2"), nil), container("warmup", cpu("500m"), nil)},
+				Containers:     []v1.Container{container("app", cpu("1"), nil), container("proxy", cpu("500m"), nil)},
+			},
+			wantRequests: cpu("2"),
+			wantLimits:   v1.ResourceList{},
+		},
+		{
+			name: "app containers win over a smaller init container",
+			podSpec: v1.PodSpec{
+				InitContainers: []v1.Container{container("migrate", cpuMemory("1", "256Mi"), nil)},
+				Containers:     []v1.Container{container("app", cpuMemory("500m", "1Gi"), nil), container("proxy", cpuMemory("700m", "64Mi"), nil)},
+			},
+			wantRequests: cpuMemory("1200m", "1088Mi"),
+			wantLimits:   v1.ResourceList{},
+		},
+		{
+			name: "sidecar before an init container runs alongside it",
+			podSpec: v1.PodSpec{
+				InitContainers: []v1.Container{sidecar("mesh", cpu("100m"), nil), container("migrate", cpu("500m"), nil)},
+				Containers:     []v1.Container{container("app", cpu("200m"), nil)},
+			},
+			wantRequests: cpu("600m"),
+			wantLimits:   v1.ResourceList{},
+		},
+		{
+			name: "sidecar after an init container only starts once it's done",
+			podSpec: v1.PodSpec{
+				InitContainers: []v1.Container{container("migrate", cpu("500m"), nil), sidecar("mesh", cpu("100m"), nil)},
+				Containers:     []v1.Container{container("app", cpu("200m"), nil)},
+			},
+			wantRequests: cpu("500m"),
+			wantLimits:   v1.ResourceList{},
+		},
+		{
+			name: "sidecars are added to the app containers",
+			podSpec: v1.PodSpec{
+				InitContainers: []v1.Container{sidecar("mesh", cpuMemory("100m", "64Mi"), cpuMemory("200m", "64Mi"))},
+				Containers:     []v1.Container{container("app", cpuMemory("1", "1Gi"), cpuMemory("2", "1Gi"))},
+			},
+			wantRequests: cpuMemory("1100m", "1088Mi"),
+			wantLimits:   cpuMemory("2200m", "1088Mi"),
+		},
+		{
+			name: "requests are defaulted from the limits",
+			podSpec: v1.PodSpec{
+				Containers: []v1.Container{container("app", nil, cpuMemory("1", "1Gi"))},
+			},
+			wantRequests: cpuMemory("1", "1Gi"),
+			wantLimits:   cpuMemory("1", "1Gi"),
+		},
+		{
+			name: "overhead is only added to the limits which are set",
+			podSpec: v1.PodSpec{
+				Containers: []v1.Container{container("app", cpu("100m"), v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")})},
+				Overhead:   cpuMemory("50m", "10Mi"),
+			},
+			wantRequests: cpuMemory("150m", "138Mi"),
+			wantLimits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("138Mi")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests, limits := effectivePodResources(test.podSpec)
+			if !equalResourceLists(requests, test.wantRequests) {
+				t.Errorf("requests = %v, want %v", requests, test.wantRequests)
+			}
+			if !equalResourceLists(limits, test.wantLimits) {
+				t.Errorf("limits = %v, want %v", limits, test.wantLimits)
+			}
+		})
+	}
+}
+
+func TestSidecarPodResources(t *testing.T) {
+	podSpec := v1.PodSpec{
+		InitContainers: []v1.Container{
+			sidecar("mesh", cpu("100m"), cpu("200m")),
+			container("migrate", cpu("2"), nil),
+			sidecar("log-shipper", cpu("50m"), nil),
+		},
+		Containers: []v1.Container{container("app", cpu("1"), nil)},
+	}
+	requests, limits := sidecarPodResources(podSpec)
+	if !equalResourceLists(requests, cpu("150m")) {
+		t.Errorf("requests = %v, want %v", requests, cpu("150m"))
+	}
+	if !equalResourceLists(limits, cpu("200m")) {
+		t.Errorf("limits = %v, want %v", limits, cpu("200m"))
+	}
+}
+
+func TestQOSClass(t *testing.T) {
+	tests := []struct {
+		name    string
+		podSpec v1.PodSpec
+		want    v1.PodQOSClass
+	}{
+		{
+			name:    "nothing requested or limited",
+			podSpec: v1.PodSpec{Containers: []v1.Container{container("app", nil, nil)}},
+			want:    v1.PodQOSBestEffort,
+		},
+		{
+			name:    "requests defaulted from the limits",
+			podSpec: v1.PodSpec{Containers: []v1.Container{container("app", nil, cpuMemory("1", "1Gi"))}},
+			want:    v1.PodQOSGuaranteed,
+		},
+		{
+			name:    "requests equal to the limits",
+			podSpec: v1.PodSpec{Containers: []v1.Container{container("app", cpuMemory("1", "1Gi"), cpuMemory("1", "1Gi"))}},
+			want:    v1.PodQOSGuaranteed,
+		},
+		{
+			name:    "requests below the limits",
+			podSpec: v1.PodSpec{Containers: []v1.Container{container("app", cpuMemory("500m", "1Gi"), cpuMemory("1", "1Gi"))}},
+			want:    v1.PodQOSBurstable,
+		},
+		{
+			name:    "only the cpu limited",
+			podSpec: v1.PodSpec{Containers: []v1.Container{container("app", nil, cpu("1"))}},
+			want:    v1.PodQOSBurstable,
+		},
+		{
+			name: "an init container without limits",
+			podSpec: v1.PodSpec{
+				InitContainers: []v1.Container{container("migrate", cpu("100m"), nil)},
+				Containers:     []v1.Container{container("app", nil, cpuMemory("1", "1Gi"))},
+			},
+			want: v1.PodQOSBurstable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := qosClass(test.podSpec); got != test.want {
+				t.Errorf("qosClass() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
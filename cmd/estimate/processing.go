@@ -2,6 +2,7 @@ package estimate
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -9,46 +10,89 @@ import (
 	table "github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	yaml "sigs.k8s.io/yaml"
-
-	"k8s.io/kubernetes/pkg/apis/autoscaling"
 	// "github.com/spf13/cobra"
 )
 
-func ProcessManifest(manifestPath string, reportVerbosity int) {
-
-	//////////////// Reading whole file in one go
-	/*
-		Reading the whole file in one go. Not optimal when the manifest is really big (like 10k-20k lines big)
-
-		Todov1: use delimiter based reading:
-		- https://www.bacancytechnology.com/qanda/golang/reading-a-file-line-by-line-in-go
-		- https://stackoverflow.com/questions/1821811/how-to-read-write-from-to-a-file-using-go
-
-	*/
-	yamlRawdata, err := os.ReadFile(manifestPath)
+// Reads the rendered manifests & works out the resources needed by every object in them
+// along with the totals. Rendering the result is left to the caller.
+// manifestPaths can be files, directories (searched recursively for .yaml, .yml & .json files),
+// glob patterns or `-` for stdin. Objects of all of them end up in the same AllObjDetail.
+//
+// Documents which can't be parsed are skipped (& reported on stderr), the result is still returned
+// along with an error saying how many were skipped. The result is nil only when an input can't be read.
+func ProcessManifest(manifestPaths []string, nodeCount NodeRange, defaultNamespace string) (*AllObjDetail, error) {
+	computedFileResult := newAllObjDetail(nodeCount, defaultNamespace)
+	skipped, err := computedFileResult.addManifestFiles(manifestPaths)
 	if err != nil {
-		fmt.Printf("Error reading YAML file: %v\n", err)
+		return nil, err
 	}
-	// fmt.Println("Printing raw data after reading the file: ", yamlRawdata)
-	manifests := splitYAML(string(yamlRawdata))
+	computedFileResult.computeAllTotals()
+	return computedFileResult, skippedError(skipped)
+}
 
+func newAllObjDetail(nodeCount NodeRange, defaultNamespace string) *AllObjDetail {
 	var computedFileResult *AllObjDetail = &AllObjDetail{}
 	computedFileResult.Objects = make(map[string][]*ObjDetail)
+	computedFileResult.RuntimeClasses = make(map[string]v1.ResourceList)
+	computedFileResult.NodeCount = nodeCount
+	computedFileResult.DefaultNamespace = defaultNamespace
+	return computedFileResult
+}
 
-	// var computedObjKind string
+// Processes every object of a (multi document) manifest, eg: a file, stdin, or a chart rendered in-process.
+// The manifest is streamed a document at a time. source is recorded on the objects & used in messages.
+// Returns the no. of documents which couldn't be parsed, errors are only for input which can't be read.
+func (a *AllObjDetail) addManifest(r io.Reader, source string) (int, error) {
+	a.currentSource = source
+
+	var skipped, docNum int
+	err := readDocuments(r, func(doc []byte) {
+		docNum++
+		if err := processEachObject(doc, a); err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping document %d of %s: %v\n", docNum, source, err)
+			skipped++
+		}
+	})
+	if err != nil {
+		return skipped, fmt.Errorf("error reading %s: %w", source, err)
+	}
+	return skipped, nil
+}
 
-	for _, manifest := range manifests {
-		processEachObject([]byte(manifest), computedFileResult)
-		// fmt.Println("computedFileResult: ", *&computedFileResult.Objects, "\n")
+// Adds the RuntimeClass overheads, multiplies every object by its replica counts & adds everything up.
+// Called once all the inputs have been processed
+func (a *AllObjDetail) computeAllTotals() {
+	a.applyRuntimeClassOverhead()
+	for _, obj := range a.SortedObjects() {
+		obj.computeTotals()
+	}
+	a.computeGrossTotalResources()
+}
 
+func skippedError(skipped int) error {
+	if skipped > 0 {
+		return fmt.Errorf("%d document(s) couldn't be parsed", skipped)
 	}
-	renderOutput(reportVerbosity, computedFileResult) // print tabular summary
+	return nil
+}
+
+// Kinds processEachObject reads a pod spec from. Every other kind (eg: an argoproj Rollout or
+// some other custom resource with a scale subresource) is skipped, even when it's in the manifest
+var workloadKinds = map[string]bool{
+	"Deployment": true, "StatefulSet": true, "DaemonSet": true, "Pod": true, "Job": true, "CronJob": true, "ScaledJob": true,
+}
 
+// Whether objects of the kind get their pod spec parsed, i.e. whether an HPA
+// targeting that kind can be matched up with its target
+func IsWorkloadKind(kind string) bool {
+	return workloadKinds[kind]
 }
 
 // This method processes each k8s object and
@@ -58,7 +102,7 @@ func ProcessManifest(manifestPath string, reportVerbosity int) {
 // the `ObjDetail` type, which is appended to bigger type  `AllObjDetail`
 // It outsources actual resources extraction to a separate fcuntion
 // since podspec is common to both deployments and statefulsets
-func processEachObject(yamlRawdata []byte, computedFileResult *AllObjDetail) {
+func processEachObject(yamlRawdata []byte, computedFileResult *AllObjDetail) error {
 
 	type checkObjKind struct {
 		APIVersion string `yaml:"apiVersion"`
@@ -66,16 +110,19 @@ func processEachObject(yamlRawdata []byte, computedFileResult *AllObjDetail) {
 	}
 	tmpChkObjKind := checkObjKind{}
 	if err := yaml.Unmarshal(yamlRawdata, &tmpChkObjKind); err != nil {
-		fmt.Println("Error unmarshalling raw data to check object kind. Error: ", err)
+		return fmt.Errorf("error unmarshalling raw data to check object kind: %w", err)
 	}
 
 	// TODO v2: The following section feels hacky especially when consideing that more items can popup in future. Go read about  interfaces well & other OSS code  and see if this can be improved.
+	if isList(tmpChkObjKind.Kind) {
+		return processList(yamlRawdata, computedFileResult)
+	}
+
 	switch tmpChkObjKind.Kind {
 	case "StatefulSet":
 		var inputManifestObj appsv1.StatefulSet = appsv1.StatefulSet{}
 		if err := yaml.Unmarshal(yamlRawdata, &inputManifestObj); err != nil {
-			fmt.Println("Error unmarshalling yaml data into deployment struct type: ", err)
-			return
+			return fmt.Errorf("error unmarshalling yaml data into deployment struct type: %w", err)
 		}
 
 		var podTemplSpec v1.PodSpec = inputManifestObj.Spec.Template.Spec
@@ -86,18 +133,35 @@ func processEachObject(yamlRawdata []byte, computedFileResult *AllObjDetail) {
 			replicas = -1
 		}
 
-		if err := processPodSpec(podTemplSpec, inputManifestObj.Name, inputManifestObj.Kind, replicas, computedFileResult); err != nil {
-			fmt.Printf("Error processing PodSpec for an Objectc Kind %s: %v\n", tmpChkObjKind.Kind, err)
-			return
-		} else {
-			return
+		if err := processPodSpec(podTemplSpec, inputManifestObj.Name, computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, replicas, inputManifestObj.Annotations, computedFileResult); err != nil {
+			return fmt.Errorf("error processing PodSpec for an Objectc Kind %s: %w", tmpChkObjKind.Kind, err)
 		}
 
+		// Rolling updates of a StatefulSet (incl. maxUnavailable & partition) delete a pod before
+		// recreating it, so it never runs more pods than spec.replicas & its peak is the replica count.
+
+		// Every pod gets its own PVC from each of the volumeClaimTemplates
+		computedObj := computedFileResult.chkIfObjAdded(computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, inputManifestObj.Name)
+		for _, claimTemplate := range inputManifestObj.Spec.VolumeClaimTemplates {
+			addClaimStorage(computedObj.Storage, claimTemplate.Spec)
+		}
+		return nil
+
+	case "RuntimeClass":
+		return processRuntimeClass(yamlRawdata, computedFileResult)
+
+	case "PersistentVolumeClaim":
+		var inputManifestObj v1.PersistentVolumeClaim = v1.PersistentVolumeClaim{}
+		if err := yaml.Unmarshal(yamlRawdata, &inputManifestObj); err != nil {
+			return fmt.Errorf("error unmarshalling yaml data into PersistentVolumeClaim struct type: %w", err)
+		}
+		processPVC(inputManifestObj, computedFileResult)
+		return nil
+
 	case "Deployment":
 		var inputManifestObj appsv1.Deployment = appsv1.Deployment{}
 		if err := yaml.Unmarshal(yamlRawdata, &inputManifestObj); err != nil {
-			fmt.Println("Error unmarshalling yaml data into deployment struct type: ", err)
-			return
+			return fmt.Errorf("error unmarshalling yaml data into deployment struct type: %w", err)
 		}
 
 		var podTemplSpec v1.PodSpec = inputManifestObj.Spec.Template.Spec
@@ -108,41 +172,87 @@ func processEachObject(yamlRawdata []byte, computedFileResult *AllObjDetail) {
 			replicas = -1
 		}
 
-		if err := processPodSpec(podTemplSpec, inputManifestObj.Name, inputManifestObj.Kind, replicas, computedFileResult); err != nil {
-			fmt.Printf("Error processing PodSpec for an Objectc Kind %s: %v\n", tmpChkObjKind.Kind, err)
-			return
-		} else {
-			return
+		maxSurge, err := deploymentMaxSurge(inputManifestObj.Spec.Strategy)
+		if err != nil {
+			return fmt.Errorf("error reading the rollout strategy of Deployment %s: %w", inputManifestObj.Name, err)
 		}
 
-	case "HorizontalPodAutoscaler":
-		var inputManifestObj autoscaling.HorizontalPodAutoscaler = autoscaling.HorizontalPodAutoscaler{}
+		if err := processPodSpec(podTemplSpec, inputManifestObj.Name, computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, replicas, inputManifestObj.Annotations, computedFileResult); err != nil {
+			return fmt.Errorf("error processing PodSpec for an Objectc Kind %s: %w", tmpChkObjKind.Kind, err)
+		}
+		computedFileResult.chkIfObjAdded(computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, inputManifestObj.Name).MaxSurge = maxSurge
+		return nil
+
+	case "DaemonSet":
+		var inputManifestObj appsv1.DaemonSet = appsv1.DaemonSet{}
 		if err := yaml.Unmarshal(yamlRawdata, &inputManifestObj); err != nil {
-			fmt.Println("Error unmarshalling yaml data into deployment struct type: ", err)
-			return
+			return fmt.Errorf("error unmarshalling yaml data into DaemonSet struct type: %w", err)
+		}
+
+		// A DaemonSet runs a pod per node, so its "replica count" is really the node count.
+		// A fixed count is treated like spec.replicas, whereas a range fills up the min/max
+		// columns the same way an HPA would (HPAs can't target DaemonSets, so there's no clash).
+		nodes := computedFileResult.NodeCount
+		var replicas int32 = -1
+		if nodes.Min == nodes.Max {
+			replicas = nodes.Min
+		}
+		if nodes.Max == -1 {
+			fmt.Fprintf(os.Stderr, "No --nodes value provided, so DaemonSet %s is left out of the totals\n", inputManifestObj.Name)
+		}
+
+		maxSurge, err := daemonSetMaxSurge(inputManifestObj.Spec.UpdateStrategy)
+		if err != nil {
+			return fmt.Errorf("error reading the update strategy of DaemonSet %s: %w", inputManifestObj.Name, err)
+		}
+
+		if err := processPodSpec(inputManifestObj.Spec.Template.Spec, inputManifestObj.Name, computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, replicas, inputManifestObj.Annotations, computedFileResult); err != nil {
+			return fmt.Errorf("error processing PodSpec for an Objectc Kind %s: %w", tmpChkObjKind.Kind, err)
 		}
+		computedObj := computedFileResult.chkIfObjAdded(computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, inputManifestObj.Name)
+		computedObj.MaxSurge = maxSurge
+		if nodes.Min != nodes.Max {
+			computedObj.MinReplicas = nodes.Min
+			computedObj.MaxReplicas = nodes.Max
+		}
+		return nil
 
-		var hpaSpec autoscaling.HorizontalPodAutoscalerSpec = inputManifestObj.Spec
-		if err := processHPASpec(hpaSpec, computedFileResult); err != nil {
-			fmt.Printf("Unable to process Spec for the HPA object %s due to Error: %v\n", inputManifestObj.Name, err)
-			return
+	case "HorizontalPodAutoscaler":
+		hpaMeta, hpaSpec, err := decodeHPA(tmpChkObjKind.APIVersion, yamlRawdata)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling yaml data into HorizontalPodAutoscaler struct type: %w", err)
+		}
+
+		if err := processHPASpec(hpaMeta, hpaSpec, computedFileResult); err != nil {
+			return fmt.Errorf("unable to process Spec for the HPA object %s: %w", hpaMeta.Name, err)
 		} else {
-			return
+			return nil
+		}
+
+	case "ScaledObject":
+		hpaMeta, hpaSpec, err := decodeScaledObject(yamlRawdata)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling yaml data into ScaledObject struct type: %w", err)
+		}
+		if err := processHPASpec(hpaMeta, hpaSpec, computedFileResult); err != nil {
+			return fmt.Errorf("unable to process Spec for the ScaledObject %s: %w", hpaMeta.Name, err)
 		}
-	
+		return nil
+
+	case "ScaledJob":
+		return processScaledJob(yamlRawdata, computedFileResult)
+
 	case "Pod":
 		var inputManifestObj v1.Pod = v1.Pod{}
 		if err := yaml.Unmarshal(yamlRawdata, &inputManifestObj); err != nil {
-			fmt.Println("Error unmarshalling yaml data into Pod struct type: " , err)
-			return 
+			return fmt.Errorf("error unmarshalling yaml data into Pod struct type: %w", err)
 		}
 		var replicas int32 = 1
 
-		if err := processPodSpec(inputManifestObj.Spec, inputManifestObj.Name, inputManifestObj.Kind,replicas, computedFileResult); err != nil {
-			fmt.Printf("Unable to process spec for kind %s and object %s", inputManifestObj.Kind, inputManifestObj.Name)
-			return
+		if err := processPodSpec(inputManifestObj.Spec, inputManifestObj.Name, computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, replicas, inputManifestObj.Annotations, computedFileResult); err != nil {
+			return fmt.Errorf("unable to process spec for kind %s and object %s: %w", inputManifestObj.Kind, inputManifestObj.Name, err)
 		} else {
-			return
+			return nil
 		}
 
 	case "Job":
@@ -150,110 +260,136 @@ func processEachObject(yamlRawdata []byte, computedFileResult *AllObjDetail) {
 
 		var inputManifestObj batchv1.Job = batchv1.Job{}
 		if err := yaml.Unmarshal(yamlRawdata, &inputManifestObj); err != nil {
-			fmt.Println("Error unmarshalling yaml data into Job datatype: ", err)
+			return fmt.Errorf("error unmarshalling yaml data into Job datatype: %w", err)
+		}
+		replicas, err := jobPods(inputManifestObj.Spec)
+		if err != nil {
+			return fmt.Errorf("invalid spec of Job %s: %w", inputManifestObj.Name, err)
 		}
-		var replicas int32 = 1
 
-		if err := processPodSpec(inputManifestObj.Spec.Template.Spec, inputManifestObj.Name, inputManifestObj.Kind, replicas, computedFileResult ); err != nil {
-			fmt.Printf("Unable to process spec for kind %s and object %s", inputManifestObj.Kind, inputManifestObj.Name)
-			return
+		if err := processPodSpec(inputManifestObj.Spec.Template.Spec, inputManifestObj.Name, computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, replicas, inputManifestObj.Annotations, computedFileResult); err != nil {
+			return fmt.Errorf("unable to process spec for kind %s and object %s: %w", inputManifestObj.Kind, inputManifestObj.Name, err)
 		} else {
-			return
+			return nil
 		}
 	case "CronJob":
 		var inputManifestObj batchv1.CronJob = batchv1.CronJob{}
 		if err := yaml.Unmarshal(yamlRawdata, &inputManifestObj); err != nil {
-			fmt.Println("Error unmarshalling yaml data into CronJob datatype: ", err)
+			return fmt.Errorf("error unmarshalling yaml data into CronJob datatype: %w", err)
+		}
+		// Replicas are the pods of a single run, overlapping runs only add up in the peak
+		replicas, err := jobPods(inputManifestObj.Spec.JobTemplate.Spec)
+		if err != nil {
+			return fmt.Errorf("invalid jobTemplate of CronJob %s: %w", inputManifestObj.Name, err)
+		}
+		runs, err := overlappingRuns(inputManifestObj)
+		if err != nil {
+			return fmt.Errorf("invalid spec of CronJob %s: %w", inputManifestObj.Name, err)
+		}
+		if runs == -1 {
+			fmt.Fprintf(os.Stderr, "CronJob %s allows overlapping runs (concurrencyPolicy Allow) without an activeDeadlineSeconds, so its worst case can't be bounded\n", inputManifestObj.Name)
 		}
-		var replicas int32 = 1
 
-		if err := processPodSpec(inputManifestObj.Spec.JobTemplate.Spec.Template.Spec, inputManifestObj.Name, inputManifestObj.Kind, replicas, computedFileResult); err != nil {
-			fmt.Printf("Unable to process spec for kind %s and object %s", inputManifestObj.Kind, inputManifestObj.Name)
-			return
-		} else {
-			return
+		if err := processPodSpec(inputManifestObj.Spec.JobTemplate.Spec.Template.Spec, inputManifestObj.Name, computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, replicas, inputManifestObj.Annotations, computedFileResult); err != nil {
+			return fmt.Errorf("unable to process spec for kind %s and object %s: %w", inputManifestObj.Kind, inputManifestObj.Name, err)
 		}
+		computedObj := computedFileResult.chkIfObjAdded(computedFileResult.namespaceOf(inputManifestObj.ObjectMeta), inputManifestObj.Kind, inputManifestObj.Name)
+		computedObj.CronJobSpec = &inputManifestObj.Spec
+		computedObj.ConcurrentRuns = runs
+		return nil
 		
 	default:
 		// fmt.Println("Neither of preexisting object kinds match. Object kind: ", tmpChkObjKind.Kind)
-		return
+		return nil
 	}
 
 }
 
-func processHPASpec(hpaSpec autoscaling.HorizontalPodAutoscalerSpec, computedFileResult *AllObjDetail) error {
+func processHPASpec(hpaMeta metav1.ObjectMeta, hpaSpec autoscalingv2.HorizontalPodAutoscalerSpec, computedFileResult *AllObjDetail) error {
+
+	// minReplicas is optional & the API server defaults it to 1
+	var minReplicas int32 = 1
+	if hpaSpec.MinReplicas != nil {
+		minReplicas = *hpaSpec.MinReplicas
+	}
+	if hpaSpec.MaxReplicas < minReplicas {
+		return fmt.Errorf("maxReplicas (%d) is less than minReplicas (%d)", hpaSpec.MaxReplicas, minReplicas)
+	}
 
-	computedObj := computedFileResult.chkIfObjAdded(hpaSpec.ScaleTargetRef.Kind, hpaSpec.ScaleTargetRef.Name)
+	// scaleTargetRef can only point to an object in the HPA's own namespace
+	namespace := computedFileResult.namespaceOf(hpaMeta)
+	computedObj := computedFileResult.chkIfObjAdded(namespace, hpaSpec.ScaleTargetRef.Kind, hpaSpec.ScaleTargetRef.Name)
 
 	if computedObj == nil {
 		// fmt.Println("computedObj is nil: ", *computedObj)
+		// The target shows up later in the manifest, processPodSpec fills up the rest once it does
 		computedObj = &ObjDetail{
-			MinReplicas: *hpaSpec.MinReplicas,
-			MaxReplicas: hpaSpec.MaxReplicas,
-			ObjName:     hpaSpec.ScaleTargetRef.Name,
-			ObjKind:     hpaSpec.ScaleTargetRef.Kind,
-			CpuReq:      0.0,
-			CpuLim:      0.0,
-			MemReq:      0.0,
-			MemLim:      0.0,
+			Replicas:     -1,
+			ObjName:      hpaSpec.ScaleTargetRef.Name,
+			ObjNamespace: namespace,
+			ObjKind:      hpaSpec.ScaleTargetRef.Kind,
 		}
 		(*computedFileResult).Objects[hpaSpec.ScaleTargetRef.Kind] = append((*computedFileResult).Objects[hpaSpec.ScaleTargetRef.Kind], computedObj)
-		return nil
-	} else {
-		// fmt.Println("compuedObj already exists (so will just edit up min/max replicas): ", *computedObj)
-		computedObj.MinReplicas = *hpaSpec.MinReplicas
-		computedObj.MaxReplicas = hpaSpec.MaxReplicas
-		return nil
 	}
+
+	// fmt.Println("compuedObj already exists (so will just edit up min/max replicas): ", *computedObj)
+	computedObj.MinReplicas = minReplicas
+	computedObj.MaxReplicas = hpaSpec.MaxReplicas
+	computedObj.HPAPresent = true
+	computedObj.HPAName = hpaMeta.Name
+	computedObj.HPABehavior = hpaSpec.Behavior
+	computedObj.HPAAnnotations = hpaMeta.Annotations
+	return nil
 }
-func processPodSpec(podTemplSpec v1.PodSpec, objectName string, objectKind string, objReplicas int32, computedFileResult *AllObjDetail) error {
 
-	fmt.Println("objname: ", objectName)
-	var cpuReq, cpuLim, memReq, memLim resource.Quantity = resource.Quantity{}, resource.Quantity{}, resource.Quantity{}, resource.Quantity{} // units of Mi and m
-	for _, container := range podTemplSpec.Containers {
-		// fmt.Printf("\nreq type: %T", container.Resources.Requests.Cpu())
-		cpuReq.Add(*container.Resources.Requests.Cpu())
-		cpuLim.Add(*container.Resources.Limits.Cpu())
+func processPodSpec(podTemplSpec v1.PodSpec, objectName string, objectNamespace string, objectKind string, objReplicas int32, objAnnotations map[string]string, computedFileResult *AllObjDetail) error {
 
-		memReq.Add(*container.Resources.Requests.Memory())
-		memLim.Add(*container.Resources.Limits.Memory())
+	requests, limits := effectivePodResources(podTemplSpec)
+	naiveRequests, naiveLimits := naivePodResources(podTemplSpec)
+	sidecarRequests, sidecarLimits := sidecarPodResources(podTemplSpec)
+	emptyDirDisk, emptyDirMemory := emptyDirSizeLimits(podTemplSpec)
 
-	}
-	for _, container := range podTemplSpec.InitContainers {
-		cpuReq.Add(*container.Resources.Requests.Cpu())
-		cpuLim.Add(*container.Resources.Limits.Cpu())
-
-		memReq.Add(*container.Resources.Requests.Memory())
-		memLim.Add(*container.Resources.Limits.Memory())
-	}
+	resources := PodResources{Requests: requests, Limits: limits}
+	naiveSum := PodResources{Requests: naiveRequests, Limits: naiveLimits}
+	sidecars := PodResources{Requests: sidecarRequests, Limits: sidecarLimits}
 
 	// k8s.io/apimachinery/pkg/api/resource
-	if existingObj := computedFileResult.chkIfObjAdded(objectKind, objectName); existingObj != nil {
+	if existingObj := computedFileResult.chkIfObjAdded(objectNamespace, objectKind, objectName); existingObj != nil {
 		// fmt.Printf("Obj %s already added, so just editing it to add hpa min/max repica count", existingObj.ObjName)
 		existingObj.ObjName = objectName
 		existingObj.ObjKind = objectKind
-
-		// though the function says "ApproximateFloat64",
-		// the approximation is not that relevant here because:
-		// - your typical CPU is at best going to vary from 0.001 aka 1m
-		// 		(anything smaller is meaningless & even kubernetes rounds it off to 1m)
-		// - your typical RAM is going to vary from few MBs to Terabytes (maybe PetaByte at worst?)
-		// For both the resources, even float16 will suffice. I'm picking float32 just as a precaution.
-		existingObj.CpuReq = float32(cpuReq.AsApproximateFloat64())
-		existingObj.CpuLim = float32(cpuLim.AsApproximateFloat64())
-		existingObj.MemReq = float32(memReq.Value())
-		existingObj.MemLim = float32(memLim.Value())
+		existingObj.Replicas = objReplicas
+
+		existingObj.Resources = resources
+		existingObj.QOSClass = qosClass(podTemplSpec)
+		existingObj.EmptyDirDisk = emptyDirDisk
+		existingObj.EmptyDirMemory = emptyDirMemory
+		existingObj.NaiveSum = naiveSum
+		existingObj.SidecarResources = sidecars
+		existingObj.Overhead = podTemplSpec.Overhead
+		existingObj.Storage = ephemeralStorage(podTemplSpec)
+		existingObj.PodSpec = &podTemplSpec
+		existingObj.Annotations = objAnnotations
+		existingObj.SourceFile = computedFileResult.currentSource
 		return nil
 	} else {
 		computedObj := &ObjDetail{
-			ObjName: objectName,
-			ObjKind: objectKind,
-
-			CpuReq: float32(cpuReq.AsApproximateFloat64()),
-			CpuLim: float32(cpuLim.AsApproximateFloat64()),
-
-			MemReq: float32(memReq.Value()), // humanReadable("memory", memReq.Value()),
-			MemLim: float32(memLim.Value()), // humanReadable("memory", memLim.Value()),
+			ObjName:      objectName,
+			ObjNamespace: objectNamespace,
+			ObjKind:      objectKind,
+
+			Resources:      resources,
+			QOSClass:       qosClass(podTemplSpec),
+			EmptyDirDisk:   emptyDirDisk,
+			EmptyDirMemory: emptyDirMemory,
+
+			NaiveSum:         naiveSum,
+			SidecarResources: sidecars,
+			Overhead:         podTemplSpec.Overhead,
+			Storage:          ephemeralStorage(podTemplSpec),
+			PodSpec:          &podTemplSpec,
+			Annotations:      objAnnotations,
+			SourceFile:       computedFileResult.currentSource,
 
 			Replicas:    objReplicas,
 			MinReplicas: -1,
@@ -266,7 +402,8 @@ func processPodSpec(podTemplSpec v1.PodSpec, objectName string, objectKind strin
 
 }
 
-func renderOutput(reportVerbosity int, renderData *AllObjDetail) {
+// Prints the tabular report for the given verbosity
+func renderTable(reportVerbosity int, renderData *AllObjDetail) {
 
 	// w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
 	// fmt.Fprintln(w, "Name\tKind\tCPU\tMem")
@@ -276,136 +413,325 @@ func renderOutput(reportVerbosity int, renderData *AllObjDetail) {
 	// t.SetStyle(table.StyleColoredBright)
 	t.SetStyle(table.StyleLight)
 	t.Style().Options.SeparateRows = true
+	t.Style().Format.Footer = text.FormatDefault // keeps units & StorageClass names as is in the totals
 	// t.Style().Box.PaddingLeft = ""
 	t.Style().Box.PaddingRight = "  "
 
-	// rowConfigAutoMerge := table.RowConfig{AutoMerge: true}
+	// Every resource gets a Request & a Limit column. cpu & memory are always there,
+	// anything else (hugepages, GPUs etc.) only shows up if some object asks for it
+	resourceNames := renderData.ResourceNames()
+	headerTop := table.Row{"Namespace", "Kind", "Name", "Replicas"}
+	for _, name := range resourceNames {
+		headerTop = append(headerTop, displayName(name), displayName(name))
+	}
 
 	switch reportVerbosity {
-	// case 0:
-	// 	t.AppendHeader(table.Row{"Kind", "Name", "CPU", "CPU", "Memory", "Memory"}, rowConfigAutoMerge)
-	// 	t.AppendHeader(table.Row{"", "", "Request", "Limit", "Request", "Limit"})
-	// 	for objkind, objList := range renderData.Objects {
-	// 		for _, obj := range objList {
-	// 			t.AppendRow(table.Row{objkind, obj.ObjName, obj.CpuReq, obj.CpuLim, humanReadable("memory", obj.MemReq), humanReadable("memory", obj.MemLim)})
-	// 		}
-	// 	}
-
 	case 0:
 		fmt.Printf("Summary: Prints Replica Counts and Resource Usage at a per Object level (doesnt multiply resources by replica count nor does it show total resource usage).\n		If a certain value is not provided (or is zero), then an underscore is printed as a placeholder\n")
-		t.AppendHeader(table.Row{"Kind", "Name", "Replicas", "CPU", "CPU", "Memory", "Memory"}, table.RowConfig{AutoMerge: true})
-		t.AppendHeader(table.Row{"", "", "(Replicas / HPA Min / HPA Max)", "Request", "Limit", "Request", "Limit"})
-		for objkind, objList := range renderData.Objects {
-			for _, obj := range objList {
-				t.AppendRow(table.Row{objkind, obj.ObjName, printReplicas(obj), humanReadable("cpu", obj.CpuReq), humanReadable("cpu", obj.CpuLim), humanReadable("mem", obj.MemReq), humanReadable("mem", obj.MemLim)})
+		headerBottom := table.Row{"", "", "", "(Replicas / HPA Min / HPA Max / Peak)"}
+		for range resourceNames {
+			headerBottom = append(headerBottom, "Request", "Limit")
+		}
+		headerTop = append(headerTop, "QoS Class", "Sidecars", "Storage")
+		t.AppendHeader(headerTop, table.RowConfig{AutoMerge: true})
+		t.AppendHeader(append(headerBottom, "", "(CPU Req / Mem Req)", "(per StorageClass)"))
+		for _, obj := range renderData.SortedObjects() {
+			row := table.Row{obj.ObjNamespace, obj.ObjKind, obj.ObjName, printReplicas(obj)}
+			for _, name := range resourceNames {
+				row = append(row, humanReadable(qtyTypeOf(name), obj.Resources.Requests[name]), humanReadable(qtyTypeOf(name), obj.Resources.Limits[name]))
 			}
+			t.AppendRow(append(row, printQOSClass(obj), printSidecars(obj), printStorage(obj.Storage)))
 		}
 
 	case 1:
-		fmt.Printf("Summary: Print repiica count, total resources per object (i.e per pod resources multiplied by replica coun) and Net Total resource required by whole chart.\n         But in this case, given that HPAs are also involved, the Resource Columns for each object would show  3 numbers accounting (Replicas, HPAMin, HPAMax).\nIf a certain value is not provided (or is zero), then an underscore is printed as a placeholder\n")
-		t.AppendHeader(table.Row{"Kind", "Name", "Replicas", "CPU", "CPU", "Memory", "Memory"}, table.RowConfig{AutoMerge: true})
-		t.AppendHeader(table.Row{"", "", "(Replicas / HPA Min / HPA Max)", "Request (Replicas / Min / Max)", "Limit (Replicas / Min / Max)", "Request (Replicas / Min / Max)", "Limit (Replicas / Min / Max)"})
-
-		for objkind, objList := range renderData.Objects {
-			for _, obj := range objList {
-				obj.computeTotals()
-				t.AppendRow(table.Row{objkind, obj.ObjName, printReplicas(obj), printTotals("cpu", obj.TotalResourceForWholeObj[0]), printTotals("cpu", obj.TotalResourceForWholeObj[1]), printTotals("mem", obj.TotalResourceForWholeObj[2]), printTotals("mem", obj.TotalResourceForWholeObj[3])})
+		fmt.Printf("Summary: Print repiica count, total resources per object (i.e per pod resources multiplied by replica coun) and Net Total resource required by whole chart.\n         But in this case, given that HPAs are also involved, the Resource Columns for each object would show  4 numbers accounting (Replicas, HPAMin, HPAMax, Peak during a rollout). Objects without an HPA count at their replica count in the Min / Max totals.\nIf a certain value is not provided (or is zero), then an underscore is printed as a placeholder\n")
+		headerBottom := table.Row{"", "", "", "(Replicas / HPA Min / HPA Max / Peak)"}
+		for range resourceNames {
+			headerBottom = append(headerBottom, "Request (Replicas / Min / Max / Peak)", "Limit (Replicas / Min / Max / Peak)")
+		}
+		headerTop = append(headerTop, "QoS Class", "Storage")
+		t.AppendHeader(headerTop, table.RowConfig{AutoMerge: true})
+		t.AppendHeader(append(headerBottom, "", "per StorageClass (Replicas / Min / Max / Peak)"))
+
+		for _, obj := range renderData.SortedObjects() {
+			row := table.Row{obj.ObjNamespace, obj.ObjKind, obj.ObjName, printReplicas(obj)}
+			for _, name := range resourceNames {
+				row = append(row, printTotals(qtyTypeOf(name), obj.TotalResourceForWholeObj.Requests[name]), printTotals(qtyTypeOf(name), obj.TotalResourceForWholeObj.Limits[name]))
+			}
+			t.AppendRow(append(row, printQOSClass(obj), printStorageTotals(obj.TotalStorage)))
+		}
+		footer := table.Row{"", "", "", "Total"}
+		for _, name := range resourceNames {
+			footer = append(footer, printTotals(qtyTypeOf(name), renderData.GrossTotalResources.Requests[name]), printTotals(qtyTypeOf(name), renderData.GrossTotalResources.Limits[name]))
+		}
+		t.AppendFooter(append(footer, "", printStorageTotals(renderData.GrossTotalStorage)))
+
+	case 2:
+		fmt.Printf("Summary: Prints a per pod breakdown of every Object. Each resource column shows 2 numbers:\n         Effective (what the scheduler reserves: max(largest init container, sum of app containers) + pod overhead) / Naive (plain sum of every container incl. init containers)\nIf a certain value is not provided (or is zero), then an underscore is printed as a placeholder\n")
+		headerBottom := table.Row{"", "", "", "(Replicas / HPA Min / HPA Max / Peak)"}
+		for range resourceNames {
+			headerBottom = append(headerBottom, "Request (Effective / Naive)", "Limit (Effective / Naive)")
+		}
+		headerTop = append(headerTop, "QoS Class", "emptyDir sizeLimit", "Sidecars", "Pod Overhead", "HPA Behaviour")
+		t.AppendHeader(headerTop, table.RowConfig{AutoMerge: true})
+		t.AppendHeader(append(headerBottom, "", "(Disk / Memory)", "(CPU Req / Mem Req)", "(CPU / Mem)", "(Policies, Stabilization Window)"))
+		for _, obj := range renderData.SortedObjects() {
+			row := table.Row{obj.ObjNamespace, obj.ObjKind, obj.ObjName, printReplicas(obj)}
+			for _, name := range resourceNames {
+				row = append(row, printBreakdown(qtyTypeOf(name), obj.Resources.Requests[name], obj.NaiveSum.Requests[name]), printBreakdown(qtyTypeOf(name), obj.Resources.Limits[name], obj.NaiveSum.Limits[name]))
 			}
+			t.AppendRow(append(row, printQOSClass(obj), printBreakdown("mem", obj.EmptyDirDisk, obj.EmptyDirMemory), printSidecars(obj), printOverhead(obj), printHPABehavior(obj)))
 		}
-		renderData.computeGrossTotalResources()
-		t.AppendFooter(table.Row{"", "", "Total", printTotals("cpu", renderData.GrossTotalResources[0]), printTotals("cpu", renderData.GrossTotalResources[1]), printTotals("mem", renderData.GrossTotalResources[2]), printTotals("mem", renderData.GrossTotalResources[3])})
 	}
 
-	t.SetColumnConfigs([]table.ColumnConfig{
+	columnConfigs := []table.ColumnConfig{
 		{Number: 1, AutoMerge: true, AlignHeader: text.AlignCenter, Align: text.AlignLeft, AlignFooter: text.AlignLeft},
 		{Number: 2, AutoMerge: true, AlignHeader: text.AlignCenter, Align: text.AlignLeft, AlignFooter: text.AlignLeft},
-		{Number: 3, AutoMerge: false, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignLeft},
-		{Number: 4, AutoMerge: false, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignCenter},
-		{Number: 5, AutoMerge: false, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignCenter},
-		{Number: 6, AutoMerge: false, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignCenter},
-		{Number: 7, AutoMerge: false, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignCenter},
-	})
+		{Number: 3, AutoMerge: true, AlignHeader: text.AlignCenter, Align: text.AlignLeft, AlignFooter: text.AlignLeft},
+		{Number: 4, AutoMerge: false, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignLeft},
+	}
+	// every other column holds resource quantities, all of which are centered
+	for colNum := 5; colNum <= len(headerTop); colNum++ {
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Number: colNum, AutoMerge: false, AlignHeader: text.AlignCenter, Align: text.AlignCenter, AlignFooter: text.AlignCenter})
+	}
+	t.SetColumnConfigs(columnConfigs)
 	t.Render()
+
+	if reportVerbosity == 1 {
+		renderNamespaceTotals(resourceNames, renderData)
+		renderQOSTotals(resourceNames, renderData)
+	}
 }
 
-// For the verbosity flag value of `1`
-func printReplicas(obj *ObjDetail) string {
-	var replicaString strings.Builder
+// For the verbosity flag value of `1`.
+// Subtotals of every namespace, printed as a table of its own below the main one
+func renderNamespaceTotals(resourceNames []v1.ResourceName, renderData *AllObjDetail) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	t.Style().Box.PaddingRight = "  "
 
-	if obj.Replicas != -1 {
-		replicaString.WriteString(strconv.Itoa(int(obj.Replicas)))
-	} else {
-		replicaString.WriteString("_\t/\t")
+	headerTop, headerBottom := table.Row{"Namespace"}, table.Row{""}
+	for _, name := range resourceNames {
+		headerTop = append(headerTop, displayName(name), displayName(name))
+		headerBottom = append(headerBottom, "Request (Replicas / Min / Max / Peak)", "Limit (Replicas / Min / Max / Peak)")
+	}
+	t.AppendHeader(append(headerTop, "Storage"), table.RowConfig{AutoMerge: true})
+	t.AppendHeader(append(headerBottom, "per StorageClass (Replicas / Min / Max / Peak)"))
+
+	for _, namespace := range sortedKeys(renderData.NamespaceTotals) {
+		totals := renderData.NamespaceTotals[namespace]
+		row := table.Row{namespace}
+		for _, name := range resourceNames {
+			row = append(row, printTotals(qtyTypeOf(name), totals.Requests[name]), printTotals(qtyTypeOf(name), totals.Limits[name]))
+		}
+		t.AppendRow(append(row, printStorageTotals(renderData.NamespaceStorage[namespace])))
 	}
 
-	if obj.MinReplicas != -1 {
-		replicaString.WriteString(strconv.Itoa(int(obj.MinReplicas)))
+	columnConfigs := []table.ColumnConfig{{Number: 1, AlignHeader: text.AlignCenter, Align: text.AlignLeft}}
+	for colNum := 2; colNum <= len(headerTop)+1; colNum++ {
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Number: colNum, AlignHeader: text.AlignCenter, Align: text.AlignCenter})
+	}
+	t.SetColumnConfigs(columnConfigs)
+	fmt.Println("Subtotals per Namespace:")
+	t.Render()
+}
 
-	} else {
-		replicaString.WriteString("\t/\t_")
+// For the verbosity flag value of `1`.
+// Subtotals of every QoS class, to show how much of the manifest is evictable under node pressure
+func renderQOSTotals(resourceNames []v1.ResourceName, renderData *AllObjDetail) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	t.Style().Box.PaddingRight = "  "
+
+	headerTop, headerBottom := table.Row{"QoS Class"}, table.Row{""}
+	for _, name := range resourceNames {
+		headerTop = append(headerTop, displayName(name), displayName(name))
+		headerBottom = append(headerBottom, "Request (Replicas / Min / Max / Peak)", "Limit (Replicas / Min / Max / Peak)")
+	}
+	t.AppendHeader(headerTop, table.RowConfig{AutoMerge: true})
+	t.AppendHeader(headerBottom)
+
+	// same order as the kubelet evicts them in
+	for _, class := range []v1.PodQOSClass{v1.PodQOSBestEffort, v1.PodQOSBurstable, v1.PodQOSGuaranteed} {
+		totals, exists := renderData.QOSTotals[class]
+		if !exists {
+			continue
+		}
+		row := table.Row{string(class)}
+		for _, name := range resourceNames {
+			row = append(row, printTotals(qtyTypeOf(name), totals.Requests[name]), printTotals(qtyTypeOf(name), totals.Limits[name]))
+		}
+		t.AppendRow(row)
 	}
 
-	if obj.MaxReplicas != -1 {
-		replicaString.WriteString(strconv.Itoa(int(obj.MaxReplicas)))
-	} else {
-		replicaString.WriteString("\t/\t_")
+	columnConfigs := []table.ColumnConfig{{Number: 1, AlignHeader: text.AlignCenter, Align: text.AlignLeft}}
+	for colNum := 2; colNum <= len(headerTop); colNum++ {
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Number: colNum, AlignHeader: text.AlignCenter, Align: text.AlignCenter})
 	}
+	t.SetColumnConfigs(columnConfigs)
+	fmt.Println("Subtotals per QoS Class:")
+	t.Render()
+}
+
+// For the verbosity flag value of `1`
+func printReplicas(obj *ObjDetail) string {
+	var replicaString []string
 
-	return replicaString.String()
+	for _, count := range obj.ReplicaCounts() {
+		if count != -1 {
+			replicaString = append(replicaString, strconv.Itoa(int(count)))
+		} else {
+			replicaString = append(replicaString, "_")
+		}
+	}
+
+	return strings.Join(replicaString, "\t/\t")
 }
 
 // For the verbosity flag value `2`.
-// This method combines totals for replicas / HPAmin / HPAmax
+// This method combines totals for replicas / HPAmin / HPAmax / peak
 // together into a single string
-func printTotals(qtyType string, repMinMax [3]float32) string {
+func printTotals(qtyType string, repMinMax Bounds) string {
 	var result strings.Builder
 
-	switch qtyType {
-	case "cpu":
-		for i := range repMinMax {
-			if repMinMax[i] < 0 {
-				result.WriteString("_\t/\t")
-			} else {
-				result.WriteString(humanReadable("cpu", repMinMax[i]) + "\t/\t")
-			}
-		}
-	case "mem":
-		for i := range repMinMax {
-			if repMinMax[i] < 0 {
-				result.WriteString("_\t/\t")
-			} else {
-				result.WriteString(humanReadable("mem", repMinMax[i]) + "\t/\t")
-			}
+	for i := range repMinMax {
+		if repMinMax[i] == nil {
+			result.WriteString("_\t/\t")
+		} else {
+			result.WriteString(humanReadable(qtyType, *repMinMax[i]) + "\t/\t")
 		}
-	default:
-		result.WriteString("Something went wrong")
 	}
 	return strings.TrimSuffix(strings.TrimSpace(result.String()), "/")
 }
 
-// receives floats in byes and returns it as a human readable string
-func humanReadable(qtyType string, size float32) string {
+// For the verbosity flag value `2`.
+// Puts the scheduler-effective value next to the naive sum of all containers
+func printBreakdown(qtyType string, effective resource.Quantity, naive resource.Quantity) string {
+	return strings.TrimSpace(humanReadable(qtyType, effective)) + "\t/\t" + strings.TrimSpace(humanReadable(qtyType, naive))
+}
+
+// For the verbosity flag values `0` & `2`.
+// Shows how much of a pod's requests come from native sidecars
+func printSidecars(obj *ObjDetail) string {
+	return strings.TrimSpace(humanReadable("cpu", obj.SidecarResources.Requests[v1.ResourceCPU])) + "\t/\t" + strings.TrimSpace(humanReadable("mem", obj.SidecarResources.Requests[v1.ResourceMemory]))
+}
+
+// For the verbosity flag value `2`.
+// Pod overhead (eg: of a gVisor or Kata RuntimeClass), already included in the effective requests & limits
+func printOverhead(obj *ObjDetail) string {
+	if len(obj.Overhead) == 0 {
+		return "_"
+	}
+	return strings.TrimSpace(humanReadable("cpu", obj.Overhead[v1.ResourceCPU])) + "\t/\t" + strings.TrimSpace(humanReadable("mem", obj.Overhead[v1.ResourceMemory]))
+}
+
+// QoS class of the object's pods, placeholder for objects without pods (PVCs etc.)
+func printQOSClass(obj *ObjDetail) string {
+	if obj.QOSClass == "" {
+		return "_"
+	}
+	return string(obj.QOSClass)
+}
+
+// For the verbosity flag value `2`.
+// How fast the HPA (if any) scales the object up & down
+func printHPABehavior(obj *ObjDetail) string {
+	if !obj.HPAPresent {
+		return "_"
+	}
+	return formatHPABehavior(obj.HPABehavior)
+}
+
+// For the verbosity flag value `0`.
+// One line per StorageClass with the storage claimed per pod
+func printStorage(storage map[string]resource.Quantity) string {
+	var lines []string
+	for _, storageClass := range sortedKeys(storage) {
+		lines = append(lines, storageClass+": "+strings.TrimSpace(humanReadable("mem", storage[storageClass])))
+	}
+	if len(lines) == 0 {
+		return "_"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// For the verbosity flag value `1`.
+// One line per StorageClass with the totals for replicas / HPAmin / HPAmax / peak
+func printStorageTotals(storage map[string]Bounds) string {
+	var lines []string
+	for _, storageClass := range sortedKeys(storage) {
+		lines = append(lines, storageClass+": "+printTotals("mem", storage[storageClass]))
+	}
+	if len(lines) == 0 {
+		return "_"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Column header for a resource
+func displayName(name v1.ResourceName) string {
+	switch name {
+	case v1.ResourceCPU:
+		return "CPU"
+	case v1.ResourceMemory:
+		return "Memory"
+	case v1.ResourceEphemeralStorage:
+		return "Ephemeral Storage"
+	default:
+		return string(name)
+	}
+}
+
+// Picks how a resource's quantity should be humanised:
+// cores for cpu, binary units for anything measured in bytes
+// & a plain count for everything else (GPUs & other device plugin resources)
+func qtyTypeOf(name v1.ResourceName) string {
+	switch {
+	case name == v1.ResourceCPU:
+		return "cpu"
+	case name == v1.ResourceMemory, name == v1.ResourceEphemeralStorage, name == v1.ResourceStorage,
+		strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix):
+		return "mem"
+	default:
+		return "count"
+	}
+}
+
+// Formats an exact quantity for the table. Whole values are printed as is
+// (so they can be compared against `kubectl describe node` as is),
+// everything else is rounded off to 3 decimals of the unit
+//   - cpu: cores (millicores are shown as a fraction)
+//   - mem: bytes in binary units (Ki, Mi, Gi...)
+//   - count: plain number (GPUs, device plugin resources)
+func humanReadable(qtyType string, qty resource.Quantity) string {
+	if qty.IsZero() {
+		return "_\t"
+	}
+
 	switch qtyType {
 	case "cpu":
-		if size == -0.0 || size == 0.0 {
-			return "_\t"
-		} else {
-			return strconv.FormatFloat(float64(size), 'f', 1, 64)
-		}
+		return strconv.FormatFloat(float64(qty.MilliValue())/1000, 'f', -1, 64)
 
 	case "mem":
-		if size == 0.0 || size == -0.0 {
-			return "_\t"
-		} else {
-			units := []string{"B", "Ki", "Mi", "Gi", "Ti", "Pi"}
-			var finalQty float64 = float64(size)
-			var ct int8 = 0
-			for finalQty/1024 > 1 {
-				ct += 1
-				finalQty = finalQty / 1024
-			}
-			return strconv.FormatFloat(finalQty, 'f', 1, 64) + " " + units[ct]
+		units := []string{"B", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+		var bytes int64 = qty.Value()
+		var divisor int64 = 1
+		var ct int8 = 0
+		for int(ct) < len(units)-1 && bytes/(divisor*1024) > 0 {
+			ct += 1
+			divisor *= 1024
 		}
+		if bytes%divisor == 0 {
+			return strconv.FormatInt(bytes/divisor, 10) + " " + units[ct]
+		}
+		return strconv.FormatFloat(float64(bytes)/float64(divisor), 'f', 3, 64) + " " + units[ct]
+
+	case "count":
+		return qty.String()
 	}
 	return ""
 }
@@ -0,0 +1,94 @@
+package estimate
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Writes one row per object, meant to be loaded into spreadsheets.
+// Unlike the table, every number is in raw base units (cores for cpu, bytes for memory & storage,
+// plain counts for everything else) & every bound gets a column of its own.
+// Counts & totals which aren't known are left empty.
+//
+// Columns: namespace, kind, name, replicas, hpa_min, hpa_max, peak (most pods during a rollout),
+// then <resource>_<requests|limits>_<replicas|min|max|peak> for every resource
+// & storage_<StorageClass>_<replicas|min|max|peak> for every StorageClass, followed by qos_class & source (the input the object was found in)
+func renderCSV(w io.Writer, renderData *AllObjDetail, separator rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = separator
+
+	resourceNames := renderData.ResourceNames()
+	storageClasses := sortedKeys(renderData.GrossTotalStorage)
+
+	header := []string{"namespace", "kind", "name", "replicas", "hpa_min", "hpa_max", "peak"}
+	for _, name := range resourceNames {
+		for _, qtyType := range []string{"requests", "limits"} {
+			for _, bound := range boundNames {
+				header = append(header, string(name)+"_"+qtyType+"_"+bound)
+			}
+		}
+	}
+	for _, storageClass := range storageClasses {
+		for _, bound := range boundNames {
+			header = append(header, "storage_"+storageClass+"_"+bound)
+		}
+	}
+	header = append(header, "qos_class", "source")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, obj := range renderData.SortedObjects() {
+		row := []string{obj.ObjNamespace, obj.ObjKind, obj.ObjName}
+		for _, count := range obj.ReplicaCounts() {
+			if count < 0 {
+				row = append(row, "")
+			} else {
+				row = append(row, strconv.Itoa(int(count)))
+			}
+		}
+		for _, name := range resourceNames {
+			row = append(row, csvBounds(name, obj.TotalResourceForWholeObj.Requests[name])...)
+			row = append(row, csvBounds(name, obj.TotalResourceForWholeObj.Limits[name])...)
+		}
+		for _, storageClass := range storageClasses {
+			row = append(row, csvBounds(v1.ResourceStorage, obj.TotalStorage[storageClass])...)
+		}
+		row = append(row, string(obj.QOSClass), obj.SourceFile)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Value of each bound in base units, see csvQuantity
+func csvBounds(name v1.ResourceName, repMinMax Bounds) []string {
+	var cells []string
+	for _, qty := range repMinMax {
+		if qty == nil {
+			cells = append(cells, "")
+		} else {
+			cells = append(cells, csvQuantity(name, *qty))
+		}
+	}
+	return cells
+}
+
+// cpu is printed in cores with 3 decimals (millicores being the finest the API server keeps), eg: 1500m -> 1.500,
+// 2 -> 2.000. Everything else (bytes, device counts) is a whole no., rounded up, eg: 1Gi -> 1073741824,
+// so that every row of a column has the same scale
+func csvQuantity(name v1.ResourceName, qty resource.Quantity) string {
+	if name == v1.ResourceCPU {
+		milli := qty.MilliValue()
+		return fmt.Sprintf("%d.%03d", milli/1000, milli%1000)
+	}
+	return strconv.FormatInt(qty.Value(), 10)
+}
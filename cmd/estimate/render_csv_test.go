@@ -0,0 +1,34 @@
+package estimate
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestCSVQuantity(t *testing.T) {
+	tests := []struct {
+		name     v1.ResourceName
+		quantity string
+		want     string
+	}{
+		{name: v1.ResourceCPU, quantity: "1500m", want: "1.500"},
+		{name: v1.ResourceCPU, quantity: "2", want: "2.000"},
+		{name: v1.ResourceCPU, quantity: "50m", want: "0.050"},
+		{name: v1.ResourceCPU, quantity: "0", want: "0.000"},
+		{name: v1.ResourceMemory, quantity: "1Gi", want: "1073741824"},
+		{name: v1.ResourceMemory, quantity: "1.5G", want: "1500000000"},
+		{name: v1.ResourceMemory, quantity: "1500m", want: "2"},
+		{name: v1.ResourceStorage, quantity: "10Gi", want: "10737418240"},
+		{name: "nvidia.com/gpu", quantity: "3", want: "3"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.name)+" "+test.quantity, func(t *testing.T) {
+			if got := csvQuantity(test.name, resource.MustParse(test.quantity)); got != test.want {
+				t.Errorf("csvQuantity() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package estimate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Bumped whenever a field of the JSON report is renamed, removed or changes its meaning.
+// Adding fields doesn't bump it, so consumers should ignore fields they don't know about.
+const jsonSchemaVersion = "v1"
+
+// Top level of the `--output json` report.
+// Quantities are kept as Kubernetes quantity strings (eg: "1500m", "3Gi") so that nothing is lost
+// to float rounding. Counts & totals which aren't known (eg: no HPA) are null.
+type jsonReport struct {
+	SchemaVersion    string                `json:"schemaVersion"`
+	DefaultNamespace string                `json:"defaultNamespace"`
+	Nodes            jsonNodes             `json:"nodes"`
+	Objects          []jsonObject          `json:"objects"`
+	Totals           jsonTotals            `json:"totals"`
+	NamespaceTotals  map[string]jsonTotals `json:"namespaceTotals"`
+	QOSTotals        map[string]jsonTotals `json:"qosTotals"` // storage is always empty here
+}
+
+type jsonObject struct {
+	Kind           string            `json:"kind"`
+	Namespace      string            `json:"namespace"`
+	Name           string            `json:"name"`
+	Source         string            `json:"source"` // input the object was found in
+	Replicas       JSONCounts        `json:"replicas"`
+	ConcurrentRuns *int32            `json:"concurrentRuns"` // most overlapping runs of a CronJob (peak is the pods of all of them), null for other kinds & when unbounded
+	QOSClass       string            `json:"qosClass"`       // Guaranteed, Burstable, BestEffort or empty for objects without pods
+	HPA            *jsonHPA          `json:"hpa"`
+	PerPod         jsonResources     `json:"perPod"`   // scheduler-effective requests & limits of a single pod
+	Naive          jsonResources     `json:"naive"`    // plain sum of every container
+	Sidecars       jsonResources     `json:"sidecars"` // part of perPod contributed by native sidecars
+	Overhead       map[string]string `json:"overhead"` // part of perPod requests which is pod overhead (explicit or from the RuntimeClass)
+	EmptyDir       jsonEmptyDir      `json:"emptyDir"`
+	Storage        map[string]string `json:"storage"` // StorageClass -> storage per pod
+	Totals         jsonTotals        `json:"totals"`  // perPod & storage multiplied by the replica counts
+}
+
+// Schema: { replicas, min, max, peak }, peak being the most pods during a rollout.
+// Shared with the diff report, so that both spell counts the same way
+type JSONCounts struct {
+	Replicas *int32 `json:"replicas"`
+	Min      *int32 `json:"min"`
+	Max      *int32 `json:"max"`
+	Peak     *int32 `json:"peak"`
+}
+
+// Node count passed via --nodes, null when it wasn't
+type jsonNodes struct {
+	Min *int32 `json:"min"`
+	Max *int32 `json:"max"`
+}
+
+type jsonHPA struct {
+	Name     string                                         `json:"name"`
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior"`
+}
+
+type jsonResources struct {
+	Requests map[string]string `json:"requests"`
+	Limits   map[string]string `json:"limits"`
+}
+
+type jsonEmptyDir struct {
+	Disk   string `json:"disk"`
+	Memory string `json:"memory"`
+}
+
+type jsonTotals struct {
+	Requests map[string]JSONBounds `json:"requests"`
+	Limits   map[string]JSONBounds `json:"limits"`
+	Storage  map[string]JSONBounds `json:"storage"` // StorageClass -> { replicas, min, max, peak }
+}
+
+// Same schema as JSONCounts, but holding quantities (null when not known)
+type JSONBounds struct {
+	Replicas *string `json:"replicas"`
+	Min      *string `json:"min"`
+	Max      *string `json:"max"`
+	Peak     *string `json:"peak"`
+}
+
+// Writes the whole report as indented JSON
+func renderJSON(w io.Writer, renderData *AllObjDetail) error {
+	nodes := ToJSONCounts([4]int32{-1, renderData.NodeCount.Min, renderData.NodeCount.Max, -1})
+	report := jsonReport{
+		SchemaVersion:    jsonSchemaVersion,
+		DefaultNamespace: renderData.DefaultNamespace,
+		Nodes:            jsonNodes{Min: nodes.Min, Max: nodes.Max},
+		Objects:          []jsonObject{},
+		Totals:           toJSONTotals(renderData.GrossTotalResources, renderData.GrossTotalStorage),
+		NamespaceTotals:  map[string]jsonTotals{},
+		QOSTotals:        map[string]jsonTotals{},
+	}
+	for namespace, totals := range renderData.NamespaceTotals {
+		report.NamespaceTotals[namespace] = toJSONTotals(totals, renderData.NamespaceStorage[namespace])
+	}
+	for class, totals := range renderData.QOSTotals {
+		report.QOSTotals[string(class)] = toJSONTotals(totals, nil)
+	}
+
+	for _, obj := range renderData.SortedObjects() {
+		jsonObj := jsonObject{
+			Kind:      obj.ObjKind,
+			Namespace: obj.ObjNamespace,
+			Name:      obj.ObjName,
+			Source:    obj.SourceFile,
+			Replicas:  ToJSONCounts(obj.ReplicaCounts()),
+			QOSClass:  string(obj.QOSClass),
+			PerPod:    toJSONResources(obj.Resources),
+			Naive:     toJSONResources(obj.NaiveSum),
+			Sidecars:  toJSONResources(obj.SidecarResources),
+			Overhead:  toJSONResourceList(obj.Overhead),
+			EmptyDir:  jsonEmptyDir{Disk: obj.EmptyDirDisk.String(), Memory: obj.EmptyDirMemory.String()},
+			Storage:   map[string]string{},
+			Totals:    toJSONTotals(obj.TotalResourceForWholeObj, obj.TotalStorage),
+		}
+		if obj.ConcurrentRuns > 0 {
+			runs := obj.ConcurrentRuns
+			jsonObj.ConcurrentRuns = &runs
+		}
+		if obj.HPAPresent {
+			jsonObj.HPA = &jsonHPA{Name: obj.HPAName, Behavior: obj.HPABehavior}
+		}
+		for storageClass, size := range obj.Storage {
+			jsonObj.Storage[storageClass] = size.String()
+		}
+		report.Objects = append(report.Objects, jsonObj)
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal the report: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// Counts of -1 aren't known & turn into nulls
+func ToJSONCounts(counts [4]int32) JSONCounts {
+	var result JSONCounts
+	for i, dst := range []**int32{&result.Replicas, &result.Min, &result.Max, &result.Peak} {
+		if counts[i] >= 0 {
+			count := counts[i]
+			*dst = &count
+		}
+	}
+	return result
+}
+
+func toJSONResources(resources PodResources) jsonResources {
+	return jsonResources{Requests: toJSONResourceList(resources.Requests), Limits: toJSONResourceList(resources.Limits)}
+}
+
+func toJSONResourceList(list v1.ResourceList) map[string]string {
+	result := map[string]string{}
+	for name, qty := range list {
+		result[string(name)] = qty.String()
+	}
+	return result
+}
+
+func toJSONTotals(totals ResourceTotals, storage map[string]Bounds) jsonTotals {
+	result := jsonTotals{Requests: map[string]JSONBounds{}, Limits: map[string]JSONBounds{}, Storage: map[string]JSONBounds{}}
+	for name, repMinMax := range totals.Requests {
+		result.Requests[string(name)] = ToJSONBounds(repMinMax)
+	}
+	for name, repMinMax := range totals.Limits {
+		result.Limits[string(name)] = ToJSONBounds(repMinMax)
+	}
+	for storageClass, repMinMax := range storage {
+		result.Storage[storageClass] = ToJSONBounds(repMinMax)
+	}
+	return result
+}
+
+// Quantity strings of the bounds, the ones which aren't known (nil) turn into nulls
+func ToJSONBounds(repMinMax Bounds) JSONBounds {
+	quantityString := func(qty *resource.Quantity) *string {
+		if qty == nil {
+			return nil
+		}
+		s := qty.String()
+		return &s
+	}
+	return JSONBounds{Replicas: quantityString(repMinMax[0]), Min: quantityString(repMinMax[1]), Max: quantityString(repMinMax[2]), Peak: quantityString(repMinMax[3])}
+}
@@ -0,0 +1,77 @@
+package estimate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Writes a GitHub/GitLab flavoured markdown report, meant to be pasted into merge request comments.
+// A headline table of the gross totals comes first, followed by a collapsible section per kind
+// holding the same per object totals as `--verbosity 1`.
+func renderMarkdown(w io.Writer, renderData *AllObjDetail) error {
+	var md strings.Builder
+	resourceNames := renderData.ResourceNames()
+
+	md.WriteString("### manresca estimate\n\n")
+	md.WriteString("Totals are shown as `Replicas / HPA Min / HPA Max / Peak` (peak being the most pods during a rollout), `_` means the count isn't known.\n\n")
+	md.WriteString("| Resource | Request | Limit |\n|---|---|---|\n")
+	for _, name := range resourceNames {
+		fmt.Fprintf(&md, "| %s | %s | %s |\n", displayName(name),
+			markdownCell(printTotals(qtyTypeOf(name), renderData.GrossTotalResources.Requests[name])),
+			markdownCell(printTotals(qtyTypeOf(name), renderData.GrossTotalResources.Limits[name])))
+	}
+	for _, storageClass := range sortedKeys(renderData.GrossTotalStorage) {
+		fmt.Fprintf(&md, "| Storage: %s | %s | _ |\n", storageClass, markdownCell(printTotals("mem", renderData.GrossTotalStorage[storageClass])))
+	}
+
+	objectsByKind := map[string][]*ObjDetail{}
+	for _, obj := range renderData.SortedObjects() {
+		objectsByKind[obj.ObjKind] = append(objectsByKind[obj.ObjKind], obj)
+	}
+	for _, kind := range sortedKeys(objectsByKind) {
+		objects := objectsByKind[kind]
+		fmt.Fprintf(&md, "\n<details>\n<summary>%s (%d)</summary>\n\n", kind, len(objects))
+		md.WriteString(markdownHeader(resourceNames))
+		for _, obj := range objects {
+			row := []string{obj.ObjNamespace, obj.ObjName, printReplicas(obj)}
+			for _, name := range resourceNames {
+				row = append(row, printTotals(qtyTypeOf(name), obj.TotalResourceForWholeObj.Requests[name]), printTotals(qtyTypeOf(name), obj.TotalResourceForWholeObj.Limits[name]))
+			}
+			row = append(row, printStorageTotals(obj.TotalStorage))
+			md.WriteString(markdownRow(row))
+		}
+		md.WriteString("\n</details>\n")
+	}
+
+	_, err := io.WriteString(w, md.String())
+	return err
+}
+
+func markdownHeader(resourceNames []v1.ResourceName) string {
+	header := []string{"Namespace", "Name", "Replicas"}
+	for _, name := range resourceNames {
+		header = append(header, displayName(name)+" Request", displayName(name)+" Limit")
+	}
+	header = append(header, "Storage")
+	return markdownRow(header) + "|" + strings.Repeat("---|", len(header)) + "\n"
+}
+
+func markdownRow(cells []string) string {
+	for i := range cells {
+		cells[i] = markdownCell(cells[i])
+	}
+	return "| " + strings.Join(cells, " | ") + " |\n"
+}
+
+// The table helpers pad with tabs & put StorageClasses on separate lines,
+// neither of which survive inside a markdown table cell
+func markdownCell(cell string) string {
+	lines := strings.Split(cell, "\n")
+	for i := range lines {
+		lines[i] = strings.Join(strings.Fields(lines[i]), " ")
+	}
+	return strings.ReplaceAll(strings.Join(lines, "<br>"), "|", "\\|")
+}
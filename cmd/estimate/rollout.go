@@ -0,0 +1,72 @@
+package estimate
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Replica counts the per pod resources get multiplied by.
+// Schema: [ rep, min, max, peak ], -1 when a count isn't known
+func (obj *ObjDetail) ReplicaCounts() [4]int32 {
+	return [4]int32{obj.Replicas, obj.MinReplicas, obj.MaxReplicas, obj.PeakReplicas()}
+}
+
+// Most pods the object can run at once during a rollout: the highest known count (HPA/node max
+// if there is one, the replica count otherwise) plus the maxSurge of its rolling update.
+// A percentage is rounded up, same as the Deployment & DaemonSet controllers do it.
+// For a CronJob, it's the pods of all the runs which can overlap (not known when they can't be bounded).
+func (obj *ObjDetail) PeakReplicas() int32 {
+	count := obj.MaxReplicas
+	if count < 0 {
+		count = obj.Replicas
+	}
+	if obj.ConcurrentRuns == -1 {
+		return -1
+	}
+	if count > 0 && obj.ConcurrentRuns > 1 {
+		return count * obj.ConcurrentRuns
+	}
+	if count < 0 || obj.MaxSurge == nil {
+		return count
+	}
+	surge, err := intstr.GetScaledValueFromIntOrPercent(obj.MaxSurge, int(count), true)
+	if err != nil { // already validated while parsing
+		return count
+	}
+	return count + int32(surge)
+}
+
+// Extra pods a Deployment may create on top of its replica count while rolling out.
+// A RollingUpdate without a maxSurge defaults to 25% (same as the API server), Recreate never surges
+func deploymentMaxSurge(strategy appsv1.DeploymentStrategy) (*intstr.IntOrString, error) {
+	if strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		return nil, nil
+	}
+	maxSurge := intstr.FromString("25%")
+	if strategy.RollingUpdate != nil && strategy.RollingUpdate.MaxSurge != nil {
+		maxSurge = *strategy.RollingUpdate.MaxSurge
+	}
+	return validMaxSurge(maxSurge)
+}
+
+// Extra pods a DaemonSet may run on a node while rolling out (the old pod is only removed once
+// the new one is ready). maxSurge defaults to 0, OnDelete waits for pods to be deleted by hand
+func daemonSetMaxSurge(strategy appsv1.DaemonSetUpdateStrategy) (*intstr.IntOrString, error) {
+	if strategy.Type == appsv1.OnDeleteDaemonSetStrategyType || strategy.RollingUpdate == nil || strategy.RollingUpdate.MaxSurge == nil {
+		return nil, nil
+	}
+	return validMaxSurge(*strategy.RollingUpdate.MaxSurge)
+}
+
+func validMaxSurge(maxSurge intstr.IntOrString) (*intstr.IntOrString, error) {
+	surge, err := intstr.GetScaledValueFromIntOrPercent(&maxSurge, 100, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxSurge %s: %w", maxSurge.String(), err)
+	}
+	if surge < 0 {
+		return nil, fmt.Errorf("invalid maxSurge %s: can't be negative", maxSurge.String())
+	}
+	return &maxSurge, nil
+}
@@ -0,0 +1,173 @@
+package estimate
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func intOrStringPtr(value intstr.IntOrString) *intstr.IntOrString {
+	return &value
+}
+
+// maxSurge as a string, nil ones being "none"
+func surgeString(maxSurge *intstr.IntOrString) string {
+	if maxSurge == nil {
+		return "none"
+	}
+	return maxSurge.String()
+}
+
+func TestDeploymentMaxSurge(t *testing.T) {
+	rollingUpdate := func(maxSurge intstr.IntOrString) appsv1.DeploymentStrategy {
+		return appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge},
+		}
+	}
+	tests := []struct {
+		name     string
+		strategy appsv1.DeploymentStrategy
+		want     string
+		wantErr  bool
+	}{
+		{name: "no strategy defaults to 25%", strategy: appsv1.DeploymentStrategy{}, want: "25%"},
+		{name: "rolling update without maxSurge", strategy: appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType, RollingUpdate: &appsv1.RollingUpdateDeployment{}}, want: "25%"},
+		{name: "recreate never surges", strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}, want: "none"},
+		{name: "int", strategy: rollingUpdate(intstr.FromInt32(2)), want: "2"},
+		{name: "percent", strategy: rollingUpdate(intstr.FromString("50%")), want: "50%"},
+		{name: "zero", strategy: rollingUpdate(intstr.FromInt32(0)), want: "0"},
+		{name: "negative int", strategy: rollingUpdate(intstr.FromInt32(-1)), wantErr: true},
+		{name: "negative percent", strategy: rollingUpdate(intstr.FromString("-10%")), wantErr: true},
+		{name: "string without a percent", strategy: rollingUpdate(intstr.FromString("2")), wantErr: true},
+		{name: "not a number", strategy: rollingUpdate(intstr.FromString("lots%")), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := deploymentMaxSurge(test.strategy)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("deploymentMaxSurge() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && surgeString(got) != test.want {
+				t.Errorf("deploymentMaxSurge() = %s, want %s", surgeString(got), test.want)
+			}
+		})
+	}
+}
+
+func TestDaemonSetMaxSurge(t *testing.T) {
+	rollingUpdate := func(maxSurge intstr.IntOrString) appsv1.DaemonSetUpdateStrategy {
+		return appsv1.DaemonSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxSurge: &maxSurge},
+		}
+	}
+	tests := []struct {
+		name     string
+		strategy appsv1.DaemonSetUpdateStrategy
+		want     string
+		wantErr  bool
+	}{
+		{name: "no strategy defaults to 0", strategy: appsv1.DaemonSetUpdateStrategy{}, want: "none"},
+		{name: "rolling update without maxSurge", strategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType, RollingUpdate: &appsv1.RollingUpdateDaemonSet{}}, want: "none"},
+		{name: "on delete", strategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType, RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxSurge: intOrStringPtr(intstr.FromInt32(1))}}, want: "none"},
+		{name: "int", strategy: rollingUpdate(intstr.FromInt32(1)), want: "1"},
+		{name: "percent", strategy: rollingUpdate(intstr.FromString("10%")), want: "10%"},
+		{name: "negative", strategy: rollingUpdate(intstr.FromInt32(-2)), wantErr: true},
+		{name: "invalid percent", strategy: rollingUpdate(intstr.FromString("ten%")), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := daemonSetMaxSurge(test.strategy)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("daemonSetMaxSurge() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && surgeString(got) != test.want {
+				t.Errorf("daemonSetMaxSurge() = %s, want %s", surgeString(got), test.want)
+			}
+		})
+	}
+}
+
+func TestPeakReplicas(t *testing.T) {
+	tests := []struct {
+		name           string
+		replicas       int32
+		maxReplicas    int32
+		maxSurge       *intstr.IntOrString
+		concurrentRuns int32
+		want           int32
+	}{
+		{name: "25% of 10 rounded up", replicas: 10, maxReplicas: -1, maxSurge: intOrStringPtr(intstr.FromString("25%")), want: 13},
+		{name: "25% of 1 rounded up", replicas: 1, maxReplicas: -1, maxSurge: intOrStringPtr(intstr.FromString("25%")), want: 2},
+		{name: "25% of 4", replicas: 4, maxReplicas: -1, maxSurge: intOrStringPtr(intstr.FromString("25%")), want: 5},
+		{name: "int surge", replicas: 3, maxReplicas: -1, maxSurge: intOrStringPtr(intstr.FromInt32(2)), want: 5},
+		{name: "no surge", replicas: 3, maxReplicas: -1, want: 3},
+		{name: "zero replicas", replicas: 0, maxReplicas: -1, maxSurge: intOrStringPtr(intstr.FromString("25%")), want: 0},
+		{name: "hpa max wins over replicas", replicas: 2, maxReplicas: 8, maxSurge: intOrStringPtr(intstr.FromString("25%")), want: 10},
+		{name: "unknown replicas", replicas: -1, maxReplicas: -1, maxSurge: intOrStringPtr(intstr.FromString("25%")), want: -1},
+		{name: "overlapping cronjob runs", replicas: 2, maxReplicas: -1, concurrentRuns: 3, want: 6},
+		{name: "unbounded cronjob runs", replicas: 2, maxReplicas: -1, concurrentRuns: -1, want: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &ObjDetail{Replicas: test.replicas, MinReplicas: -1, MaxReplicas: test.maxReplicas, MaxSurge: test.maxSurge, ConcurrentRuns: test.concurrentRuns}
+			if got := obj.PeakReplicas(); got != test.want {
+				t.Errorf("PeakReplicas() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDaemonSetPeakReplicas(t *testing.T) {
+	daemonSet := func(updateStrategy string) string {
+		return `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  updateStrategy:` + updateStrategy + `
+  template:
+    spec:
+      containers:
+      - name: agent
+        image: agent
+`
+	}
+	tests := []struct {
+		name           string
+		nodes          NodeRange
+		updateStrategy string
+		want           [4]int32
+	}{
+		{name: "fixed node count", nodes: NodeRange{Min: 4, Max: 4}, updateStrategy: " {}", want: [4]int32{4, -1, -1, 4}},
+		{name: "node range without surge", nodes: NodeRange{Min: 3, Max: 10}, updateStrategy: " {}", want: [4]int32{-1, 3, 10, 10}},
+		{name: "percent surge per node over a range", nodes: NodeRange{Min: 3, Max: 10}, updateStrategy: "\n    rollingUpdate:\n      maxSurge: 25%", want: [4]int32{-1, 3, 10, 13}},
+		{name: "int surge per node over a range", nodes: NodeRange{Min: 3, Max: 10}, updateStrategy: "\n    rollingUpdate:\n      maxSurge: 1", want: [4]int32{-1, 3, 10, 11}},
+		{name: "on delete", nodes: NodeRange{Min: 3, Max: 10}, updateStrategy: "\n    type: OnDelete\n    rollingUpdate:\n      maxSurge: 1", want: [4]int32{-1, 3, 10, 10}},
+		{name: "no nodes", nodes: NodeRange{Min: -1, Max: -1}, updateStrategy: "\n    rollingUpdate:\n      maxSurge: 1", want: [4]int32{-1, -1, -1, -1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := newAllObjDetail(test.nodes, "default")
+			if err := processEachObject([]byte(daemonSet(test.updateStrategy)), result); err != nil {
+				t.Fatal(err)
+			}
+			obj := result.chkIfObjAdded("default", "DaemonSet", "agent")
+			if got := obj.ReplicaCounts(); got != test.want {
+				t.Errorf("ReplicaCounts() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	result := newAllObjDetail(NodeRange{Min: 3, Max: 10}, "default")
+	if err := processEachObject([]byte(daemonSet("\n    rollingUpdate:\n      maxSurge: -1")), result); err == nil {
+		t.Error("processEachObject() of a negative maxSurge should fail")
+	}
+}
@@ -0,0 +1,91 @@
+package estimate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	yaml "sigs.k8s.io/yaml"
+)
+
+// Records the overhead.podFixed of a RuntimeClass (node.k8s.io/v1), eg: the extra memory of a gVisor or Kata sandbox.
+// RuntimeClasses are cluster scoped, so they're only keyed by name
+func processRuntimeClass(yamlRawdata []byte, computedFileResult *AllObjDetail) error {
+	var runtimeClass nodev1.RuntimeClass
+	if err := yaml.Unmarshal(yamlRawdata, &runtimeClass); err != nil {
+		return fmt.Errorf("error unmarshalling yaml data into RuntimeClass struct type: %w", err)
+	}
+	var overhead v1.ResourceList
+	if runtimeClass.Overhead != nil {
+		overhead = runtimeClass.Overhead.PodFixed
+	}
+	computedFileResult.RuntimeClasses[runtimeClass.Name] = overhead
+	return nil
+}
+
+// Reads the RuntimeClasses of a cluster from a side file (eg: the output of `kubectl get runtimeclass -o yaml`).
+// Anything but RuntimeClasses is ignored, so the file doesn't add objects of its own to the estimate.
+func (a *AllObjDetail) addRuntimeClassFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error reading the RuntimeClass file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var errs []error
+	err = readDocuments(f, func(doc []byte) {
+		if err := a.addRuntimeClasses(doc); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("error reading the RuntimeClass file %s: %w", path, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("error parsing the RuntimeClass file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (a *AllObjDetail) addRuntimeClasses(doc []byte) error {
+	var object struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := yaml.Unmarshal(doc, &object); err != nil {
+		return err
+	}
+	if object.Kind == "RuntimeClass" {
+		return processRuntimeClass(doc, a)
+	}
+	if isList(object.Kind) {
+		for _, item := range object.Items {
+			if err := a.addRuntimeClasses(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Adds the overhead of their RuntimeClass to the pods which set a runtimeClassName, the way the RuntimeClass
+// admission controller does it. RuntimeClasses can show up after the pods using them, so this runs once everything is read.
+// Pod specs which set an overhead themselves already have it included & are left alone (same as the admission controller).
+func (a *AllObjDetail) applyRuntimeClassOverhead() {
+	for _, obj := range a.SortedObjects() {
+		if obj.PodSpec == nil || obj.PodSpec.RuntimeClassName == nil || obj.PodSpec.Overhead != nil {
+			continue
+		}
+		overhead, exists := a.RuntimeClasses[*obj.PodSpec.RuntimeClassName]
+		if !exists {
+			fmt.Fprintf(os.Stderr, "RuntimeClass %s of %s %s isn't in the manifest (nor --runtime-classes), so its overhead is left out\n", *obj.PodSpec.RuntimeClassName, obj.ObjKind, obj.ObjName)
+			continue
+		}
+		addPodOverhead(obj.Resources.Requests, obj.Resources.Limits, overhead)
+		obj.Overhead = overhead
+		obj.PodSpec.Overhead = overhead.DeepCopy()
+	}
+}
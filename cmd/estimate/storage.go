@@ -0,0 +1,63 @@
+package estimate
+
+import (
+	"fmt"
+	"os"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Label used for claims which don't set `storageClassName`,
+// i.e. the ones which will land on the cluster's default StorageClass
+const defaultStorageClass = "(default)"
+
+// Standalone PVCs are created once irrespective of how many pods mount them,
+// so they're recorded as an object of their own with a replica count of 1, which every bound falls back to
+func processPVC(pvc v1.PersistentVolumeClaim, computedFileResult *AllObjDetail) {
+	namespace := computedFileResult.namespaceOf(pvc.ObjectMeta)
+	computedObj := computedFileResult.chkIfObjAdded(namespace, pvc.Kind, pvc.Name)
+	if computedObj == nil {
+		computedObj = &ObjDetail{
+			ObjName:      pvc.Name,
+			ObjNamespace: namespace,
+			ObjKind:      pvc.Kind,
+			Replicas:     1,
+			MinReplicas:  -1,
+			MaxReplicas:  -1,
+		}
+		computedFileResult.Objects[pvc.Kind] = append(computedFileResult.Objects[pvc.Kind], computedObj)
+	}
+	computedObj.Annotations = pvc.Annotations
+	computedObj.SourceFile = computedFileResult.currentSource
+	computedObj.Storage = map[string]resource.Quantity{}
+	addClaimStorage(computedObj.Storage, pvc.Spec)
+}
+
+// Storage each pod of a workload gets via generic ephemeral volumes.
+// These are created (& deleted) along with the pod, so they scale with the replica count.
+func ephemeralStorage(podSpec v1.PodSpec) map[string]resource.Quantity {
+	storage := map[string]resource.Quantity{}
+	for _, volume := range podSpec.Volumes {
+		if volume.Ephemeral != nil && volume.Ephemeral.VolumeClaimTemplate != nil {
+			addClaimStorage(storage, volume.Ephemeral.VolumeClaimTemplate.Spec)
+		}
+	}
+	return storage
+}
+
+// Adds the storage requested by a claim to the total for its StorageClass
+func addClaimStorage(storage map[string]resource.Quantity, claimSpec v1.PersistentVolumeClaimSpec) {
+	storageClass := defaultStorageClass
+	if claimSpec.StorageClassName != nil {
+		storageClass = *claimSpec.StorageClassName
+	}
+
+	size, exists := claimSpec.Resources.Requests[v1.ResourceStorage]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Found a claim for the StorageClass %s without a storage request, counting it as zero\n", storageClass)
+	}
+	total := storage[storageClass]
+	total.Add(size)
+	storage[storageClass] = total
+}
@@ -1,31 +1,63 @@
 package estimate
 
 import (
-	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+
+	v1 "k8s.io/api/core/v1"
 )
 
-// Given a YAML file with manifests delimited by the string `---`,
-// this function splits them & returns them as a slice of strings
-func splitYAML(yamlContent string) []string {
-	var manifests []string
-	scanner := bufio.NewScanner(strings.NewReader(yamlContent))
-	var sb strings.Builder
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.TrimSpace(line) == "---" {
-			manifests = append(manifests, sb.String())
-			sb.Reset()
-		} else {
-			sb.WriteString(line + "\n")
-		}
+// Parses the value of the `--nodes` flag.
+// Accepts either a single count ("5") or an inclusive range ("3-10").
+// An empty string means the node count is unknown.
+func ParseNodeRange(nodeCount string) (NodeRange, error) {
+	nodeCount = strings.TrimSpace(nodeCount)
+	if nodeCount == "" {
+		return NodeRange{Min: -1, Max: -1}, nil
+	}
+
+	bounds := strings.SplitN(nodeCount, "-", 2)
+	min, err := strconv.ParseInt(strings.TrimSpace(bounds[0]), 10, 32)
+	if err != nil || min < 0 {
+		return NodeRange{}, fmt.Errorf("invalid node count %q: expected a non-negative number or a range like 3-10", nodeCount)
+	}
+	if len(bounds) == 1 {
+		return NodeRange{Min: int32(min), Max: int32(min)}, nil
+	}
+
+	max, err := strconv.ParseInt(strings.TrimSpace(bounds[1]), 10, 32)
+	if err != nil || max < min {
+		return NodeRange{}, fmt.Errorf("invalid node range %q: upper bound must be a number >= the lower bound", nodeCount)
 	}
+	return NodeRange{Min: int32(min), Max: int32(max)}, nil
+}
 
-	if sb.Len() > 0 {
-		manifests = append(manifests, sb.String())
+// Adds every quantity in `src` to the matching one in `dst`
+func addResourceList(dst, src v1.ResourceList) {
+	for name, qty := range src {
+		total := dst[name]
+		total.Add(qty)
+		dst[name] = total
 	}
+}
 
-	return manifests
+// Replaces quantities in `dst` with the ones in `src` wherever `src` is bigger
+func maxResourceList(dst, src v1.ResourceList) {
+	for name, qty := range src {
+		if current, exists := dst[name]; !exists || qty.Cmp(current) > 0 {
+			dst[name] = qty.DeepCopy()
+		}
+	}
+}
+
+// Map keys in sorted order, so that the report doesn't shuffle between runs
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	table "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// Exit code when lint finds at least one error severity problem.
+// Carries on from the codes in cmd/estimate/exit.go, which lint returns for parse errors
+const ExitLintErrors = 4
+
+// LintCmd represents the lint command
+var LintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the resources of a rendered manifest for common mistakes",
+	Long: `This command walks the same objects as estimate & reports resource hygiene problems:
+	containers without requests or limits, limits lower than requests, memory limits without a request,
+	pods whose memory per CPU core is outside of --mem-per-cpu & HPAs targeting objects which aren't in the manifest.
+	A rule can be suppressed by listing its ID in the manresca.io/lint-ignore annotation of the object.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		band, err := parseRatioBand(memPerCPU)
+		if err != nil {
+			return fmt.Errorf("error parsing the --mem-per-cpu flag: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Lint called for the filepath(s) %v\n", manifestPaths)
+		result, parseErr := estimate.ProcessManifest(manifestPaths, estimate.NodeRange{Min: -1, Max: -1}, namespace)
+		if result == nil {
+			return &estimate.ExitError{Code: estimate.ExitParseError, Err: parseErr}
+		}
+
+		findings := lintObjects(result, band)
+		sort.SliceStable(findings, func(i, j int) bool {
+			return severityRank[findings[i].Severity] < severityRank[findings[j].Severity]
+		})
+		renderFindings(findings)
+
+		if parseErr != nil {
+			return &estimate.ExitError{Code: estimate.ExitParseError, Err: parseErr}
+		}
+		for _, finding := range findings {
+			if finding.Severity == severityError {
+				return &estimate.ExitError{Code: ExitLintErrors, Err: fmt.Errorf("found error severity findings")}
+			}
+		}
+		return nil
+	},
+}
+
+var (
+	manifestPaths []string
+	namespace     string
+	memPerCPU     string
+)
+
+func init() {
+	LintCmd.Flags().StringArrayVarP(&manifestPaths, "filepath", "f", []string{"rendered.yml"}, "Path to a rendered manifest, a directory (searched recursively for .yaml, .yml & .json files) or a glob pattern ('-' for stdin). Can be repeated")
+	LintCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace assumed for objects which don't set one (same as 'helm template -n')")
+	LintCmd.Flags().StringVar(&memPerCPU, "mem-per-cpu", "1Gi-8Gi", "Allowed band of memory requested per CPU core requested (of a pod), as <min>-<max>")
+}
+
+func renderFindings(findings []Finding) {
+	fmt.Printf("Summary: %d finding(s). Suppress a rule for an object with the annotation %s: <rule id>,<rule id>,... (or all)\n", len(findings), ignoreAnnotation)
+	if len(findings) == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	t.Style().Box.PaddingRight = "  "
+	t.AppendHeader(table.Row{"Severity", "Rule", "Namespace", "Kind", "Name", "Container", "Message"})
+	for _, finding := range findings {
+		container := finding.Container
+		if container == "" {
+			container = "_"
+		}
+		t.AppendRow(table.Row{finding.Severity, finding.Rule, finding.Namespace, finding.Kind, finding.Name, container, finding.Message})
+	}
+	t.Render()
+}
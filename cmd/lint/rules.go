@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Annotation holding a comma separated list of rule IDs (or `all`) which shouldn't be reported for an object.
+// For hpa-target-missing it goes on the HPA, for every other rule on the workload itself.
+const ignoreAnnotation = "manresca.io/lint-ignore"
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+// Order in which findings are listed
+var severityRank = map[string]int{severityError: 0, severityWarning: 1, severityInfo: 2}
+
+// Rule IDs, these are what go into the ignore annotation
+const (
+	ruleMissingRequests           = "missing-requests"
+	ruleMissingLimits             = "missing-limits"
+	ruleLimitBelowRequest         = "limit-below-request"
+	ruleMemoryLimitWithoutRequest = "memory-limit-without-request"
+	ruleCPUMemoryRatio            = "cpu-memory-ratio"
+	ruleHPATargetMissing          = "hpa-target-missing"
+)
+
+// A single problem found on an object (or one of its containers)
+type Finding struct {
+	Severity  string
+	Rule      string
+	Namespace string
+	Kind      string
+	Name      string
+	Container string // empty for findings about the whole pod/object
+	Message   string
+}
+
+// Allowed memory per CPU core of a pod's requests, eg: 1Gi-8Gi
+type RatioBand struct {
+	Min resource.Quantity
+	Max resource.Quantity
+}
+
+// Runs every rule against every object, leaving out the suppressed findings
+func lintObjects(renderData *estimate.AllObjDetail, band RatioBand) []Finding {
+	var findings []Finding
+	for _, obj := range renderData.SortedObjects() {
+		report := func(severity string, rule string, annotations map[string]string, container string, format string, args ...interface{}) {
+			if isIgnored(annotations, rule) {
+				return
+			}
+			findings = append(findings, Finding{
+				Severity:  severity,
+				Rule:      rule,
+				Namespace: obj.ObjNamespace,
+				Kind:      obj.ObjKind,
+				Name:      obj.ObjName,
+				Container: container,
+				Message:   fmt.Sprintf(format, args...),
+			})
+		}
+
+		// an HPA creates a placeholder for its target, which only gets a pod spec once the target shows up.
+		// Targets of kinds which aren't parsed never get one, so there's no telling whether they're missing
+		if obj.PodSpec == nil {
+			if obj.HPAPresent && estimate.IsWorkloadKind(obj.ObjKind) {
+				report(severityError, ruleHPATargetMissing, obj.HPAAnnotations, "", "HPA %s targets %s %s, which isn't in the manifest", obj.HPAName, obj.ObjKind, obj.ObjName)
+			}
+			continue
+		}
+
+		for _, container := range allContainers(obj.PodSpec) {
+			lintContainer(container, func(severity string, rule string, format string, args ...interface{}) {
+				report(severity, rule, obj.Annotations, container.Name, format, args...)
+			})
+		}
+		lintRatio(obj, band, func(severity string, rule string, format string, args ...interface{}) {
+			report(severity, rule, obj.Annotations, "", format, args...)
+		})
+	}
+	return findings
+}
+
+func lintContainer(container v1.Container, report func(severity string, rule string, format string, args ...interface{})) {
+	requests, limits := container.Resources.Requests, container.Resources.Limits
+
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		_, hasRequest := requests[name]
+		_, hasLimit := limits[name]
+		// the API server defaults a missing request to the limit, so a limit alone is enough for scheduling
+		if !hasRequest && !hasLimit {
+			report(severityWarning, ruleMissingRequests, "no %s request, the scheduler reserves nothing for it", name)
+		}
+		if !hasLimit {
+			report(severityInfo, ruleMissingLimits, "no %s limit", name)
+		}
+	}
+
+	for name, limit := range limits {
+		if request, exists := requests[name]; exists && limit.Cmp(request) < 0 {
+			report(severityError, ruleLimitBelowRequest, "%s limit %s is lower than the request %s", name, limit.String(), request.String())
+		}
+	}
+
+	if limit, hasLimit := limits[v1.ResourceMemory]; hasLimit {
+		if _, hasRequest := requests[v1.ResourceMemory]; !hasRequest {
+			report(severityWarning, ruleMemoryLimitWithoutRequest, "memory limit %s without a request, the request defaults to the limit", limit.String())
+		}
+	}
+}
+
+// Memory per CPU core of the pod's (scheduler-effective) requests. Pods without either are skipped,
+// missing-requests already covers them
+func lintRatio(obj *estimate.ObjDetail, band RatioBand, report func(severity string, rule string, format string, args ...interface{})) {
+	cpu, hasCPU := obj.Resources.Requests[v1.ResourceCPU]
+	memory, hasMemory := obj.Resources.Requests[v1.ResourceMemory]
+	if !hasCPU || !hasMemory || cpu.IsZero() {
+		return
+	}
+
+	memoryPerCore := resource.NewQuantity(memory.Value()*1000/cpu.MilliValue(), resource.BinarySI)
+	if memoryPerCore.Cmp(band.Min) < 0 || memoryPerCore.Cmp(band.Max) > 0 {
+		report(severityWarning, ruleCPUMemoryRatio, "requests %s of memory per CPU core (%s cpu : %s memory), outside of %s-%s", memoryPerCore.String(), cpu.String(), memory.String(), band.Min.String(), band.Max.String())
+	}
+}
+
+func allContainers(podSpec *v1.PodSpec) []v1.Container {
+	return append(append([]v1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+}
+
+func isIgnored(annotations map[string]string, rule string) bool {
+	for _, ignored := range strings.Split(annotations[ignoreAnnotation], ",") {
+		ignored = strings.TrimSpace(ignored)
+		if ignored == rule || ignored == "all" {
+			return true
+		}
+	}
+	return false
+}
+
+// Parses a band of the form <min>-<max>, eg: 1Gi-8Gi
+func parseRatioBand(band string) (RatioBand, error) {
+	minStr, maxStr, found := strings.Cut(band, "-")
+	if !found {
+		return RatioBand{}, fmt.Errorf("%q isn't of the form <min>-<max> (eg: 1Gi-8Gi)", band)
+	}
+	min, err := resource.ParseQuantity(strings.TrimSpace(minStr))
+	if err != nil {
+		return RatioBand{}, fmt.Errorf("invalid min in %q: %w", band, err)
+	}
+	max, err := resource.ParseQuantity(strings.TrimSpace(maxStr))
+	if err != nil {
+		return RatioBand{}, fmt.Errorf("invalid max in %q: %w", band, err)
+	}
+	if max.Cmp(min) < 0 {
+		return RatioBand{}, fmt.Errorf("max is lower than min in %q", band)
+	}
+	return RatioBand{Min: min, Max: max}, nil
+}
@@ -0,0 +1,297 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/IamGroot19/manresca/cmd/estimate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func resourceList(pairs ...string) v1.ResourceList {
+	list := v1.ResourceList{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		list[v1.ResourceName(pairs[i])] = resource.MustParse(pairs[i+1])
+	}
+	return list
+}
+
+func defaultBand(t *testing.T) RatioBand {
+	t.Helper()
+	band, err := parseRatioBand("1Gi-8Gi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return band
+}
+
+func TestParseRatioBand(t *testing.T) {
+	tests := []struct {
+		band    string
+		wantMin string
+		wantMax string
+		wantErr bool
+	}{
+		{band: "1Gi-8Gi", wantMin: "1Gi", wantMax: "8Gi"},
+		{band: " 512Mi - 4Gi ", wantMin: "512Mi", wantMax: "4Gi"},
+		{band: "2Gi-2Gi", wantMin: "2Gi", wantMax: "2Gi"},
+		{band: "8Gi-1Gi", wantErr: true},
+		{band: "1Gi", wantErr: true},
+		{band: "lots-8Gi", wantErr: true},
+		{band: "1Gi-lots", wantErr: true},
+		{band: "-8Gi", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.band, func(t *testing.T) {
+			got, err := parseRatioBand(test.band)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseRatioBand() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if got.Min.Cmp(resource.MustParse(test.wantMin)) != 0 || got.Max.Cmp(resource.MustParse(test.wantMax)) != 0 {
+				t.Errorf("parseRatioBand() = %s-%s, want %s-%s", got.Min.String(), got.Max.String(), test.wantMin, test.wantMax)
+			}
+		})
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation *string // nil when the object has no ignore annotation
+		rule       string
+		want       bool
+	}{
+		{name: "no annotation", rule: ruleMissingLimits, want: false},
+		{name: "empty annotation", annotation: ptr(""), rule: ruleMissingLimits, want: false},
+		{name: "single rule", annotation: ptr("missing-limits"), rule: ruleMissingLimits, want: true},
+		{name: "another rule", annotation: ptr("missing-limits"), rule: ruleMissingRequests, want: false},
+		{name: "comma list", annotation: ptr("missing-requests,missing-limits"), rule: ruleMissingLimits, want: true},
+		{name: "comma list with spaces", annotation: ptr(" missing-requests , cpu-memory-ratio "), rule: ruleCPUMemoryRatio, want: true},
+		{name: "all", annotation: ptr("all"), rule: ruleLimitBelowRequest, want: true},
+		{name: "all in a list", annotation: ptr("missing-limits,all"), rule: ruleHPATargetMissing, want: true},
+		{name: "prefix of a rule", annotation: ptr("missing"), rule: ruleMissingLimits, want: false},
+		{name: "case matters", annotation: ptr("Missing-Limits"), rule: ruleMissingLimits, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			annotations := map[string]string{"unrelated": "missing-limits"}
+			if test.annotation != nil {
+				annotations[ignoreAnnotation] = *test.annotation
+			}
+			if got := isIgnored(annotations, test.rule); got != test.want {
+				t.Errorf("isIgnored() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestLintContainer(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests v1.ResourceList
+		limits   v1.ResourceList
+		want     []string // rule IDs, sorted
+	}{
+		{
+			name:     "requests & limits set",
+			requests: resourceList("cpu", "500m", "memory", "1Gi"),
+			limits:   resourceList("cpu", "1", "memory", "1Gi"),
+		},
+		{
+			name: "nothing set",
+			want: []string{ruleMissingLimits, ruleMissingLimits, ruleMissingRequests, ruleMissingRequests},
+		},
+		{
+			name:     "only requests",
+			requests: resourceList("cpu", "500m", "memory", "1Gi"),
+			want:     []string{ruleMissingLimits, ruleMissingLimits},
+		},
+		{
+			name:   "limits alone are enough for scheduling",
+			limits: resourceList("cpu", "1"),
+			want:   []string{ruleMissingLimits, ruleMissingRequests},
+		},
+		{
+			name:     "memory limit without a request",
+			requests: resourceList("cpu", "500m"),
+			limits:   resourceList("cpu", "1", "memory", "1Gi"),
+			want:     []string{ruleMemoryLimitWithoutRequest},
+		},
+		{
+			name:     "limits below the requests",
+			requests: resourceList("cpu", "2", "memory", "2Gi", "nvidia.com/gpu", "2"),
+			limits:   resourceList("cpu", "1", "memory", "1Gi", "nvidia.com/gpu", "1"),
+			want:     []string{ruleLimitBelowRequest, ruleLimitBelowRequest, ruleLimitBelowRequest},
+		},
+		{
+			name:     "limit equal to the request",
+			requests: resourceList("cpu", "1", "memory", "1Gi"),
+			limits:   resourceList("cpu", "1000m", "memory", "1024Mi"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			container := v1.Container{Name: "app", Resources: v1.ResourceRequirements{Requests: test.requests, Limits: test.limits}}
+			var got []string
+			lintContainer(container, func(severity string, rule string, format string, args ...interface{}) {
+				got = append(got, rule)
+			})
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("lintContainer() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLintRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests v1.ResourceList
+		want     bool
+	}{
+		{name: "within the band", requests: resourceList("cpu", "1", "memory", "4Gi"), want: false},
+		{name: "at the min", requests: resourceList("cpu", "2", "memory", "2Gi"), want: false},
+		{name: "at the max", requests: resourceList("cpu", "500m", "memory", "4Gi"), want: false},
+		{name: "below the min", requests: resourceList("cpu", "1", "memory", "512Mi"), want: true},
+		{name: "above the max", requests: resourceList("cpu", "250m", "memory", "4Gi"), want: true},
+		{name: "no memory request", requests: resourceList("cpu", "1"), want: false},
+		{name: "no cpu request", requests: resourceList("memory", "64Gi"), want: false},
+		{name: "zero cpu", requests: resourceList("cpu", "0", "memory", "1Gi"), want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &estimate.ObjDetail{Resources: estimate.PodResources{Requests: test.requests, Limits: v1.ResourceList{}}}
+			got := false
+			lintRatio(obj, defaultBand(t), func(severity string, rule string, format string, args ...interface{}) {
+				if rule != ruleCPUMemoryRatio {
+					t.Errorf("lintRatio() reported %s", rule)
+				}
+				got = true
+			})
+			if got != test.want {
+				t.Errorf("lintRatio() reported = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLintObjects(t *testing.T) {
+	manifest := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bare
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: quiet
+  annotations:
+    manresca.io/lint-ignore: missing-requests, missing-limits
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: silenced
+  annotations:
+    manresca.io/lint-ignore: all
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+        resources:
+          requests:
+            cpu: "2"
+          limits:
+            cpu: "1"
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: orphan
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: gone
+  maxReplicas: 3
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: orphan-ignored
+  annotations:
+    manresca.io/lint-ignore: hpa-target-missing
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: also-gone
+  maxReplicas: 3
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: rollout
+spec:
+  scaleTargetRef:
+    apiVersion: argoproj.io/v1alpha1
+    kind: Rollout
+    name: canary
+  maxReplicas: 3
+`
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := estimate.ProcessManifest([]string{path}, estimate.NodeRange{Min: -1, Max: -1}, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, finding := range lintObjects(result, defaultBand(t)) {
+		got = append(got, finding.Name+":"+finding.Rule)
+	}
+	sort.Strings(got)
+	want := []string{
+		"bare:missing-limits", "bare:missing-limits", "bare:missing-requests", "bare:missing-requests",
+		"gone:hpa-target-missing",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lintObjects() = %v, want %v", got, want)
+	}
+}
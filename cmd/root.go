@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"os"
+
+	"github.com/IamGroot19/manresca/cmd/cronpeak"
+	"github.com/IamGroot19/manresca/cmd/diff"
 	"github.com/IamGroot19/manresca/cmd/estimate"
+	"github.com/IamGroot19/manresca/cmd/lint"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +31,11 @@ to quickly create a Cobra application.`,
 func Execute() {
 	err := RootCmd.Execute()
 	if err != nil {
+		// commands return an ExitError when they need a specific exit code (see README)
+		var exitErr *estimate.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
@@ -41,4 +51,7 @@ func init() {
 	// when this action is called directly.
 	RootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	RootCmd.AddCommand(estimate.EstimateCmd)
+	RootCmd.AddCommand(diff.DiffCmd)
+	RootCmd.AddCommand(lint.LintCmd)
+	RootCmd.AddCommand(cronpeak.CronPeakCmd)
 }